@@ -1,3 +1,8 @@
 module d1y.io/jslike
 
 go 1.21.2
+
+require (
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/text v0.22.0
+)
@@ -2,6 +2,7 @@ package jsarray
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -192,3 +193,50 @@ func (v *JSArray[T]) LastIndexOf(val T) int {
 func (v *JSArray[T]) Concat(appendValue JSArray[T]) JSArray[T] {
 	return append(*v, appendValue...)
 }
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Array/sort
+//
+// The sort is stable, without compareFn the items are compared as strings by
+// UTF-16 code units like JS does, pass jsintl.Comparator for a locale aware
+// order.
+func (v *JSArray[T]) Sort(compareFn ...func(a, b T) int) *JSArray[T] {
+	compare := defaultCompare[T]
+	if len(compareFn) == 1 && compareFn[0] != nil {
+		compare = compareFn[0]
+	}
+	sort.SliceStable(*v, func(i, j int) bool {
+		return compare((*v)[i], (*v)[j]) < 0
+	})
+	return v
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Array/toSorted
+func (v *JSArray[T]) ToSorted(compareFn ...func(a, b T) int) *JSArray[T] {
+	var dist = make(JSArray[T], v.Length())
+	copy(dist, *v)
+	return dist.Sort(compareFn...)
+}
+
+func defaultCompare[T comparable](a, b T) int {
+	x, y := []rune(fmt.Sprint(a)), []rune(fmt.Sprint(b))
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] == y[i] {
+			continue
+		}
+		if diff := utf16Unit(x[i]) - utf16Unit(y[i]); diff != 0 {
+			return diff
+		}
+		// same high surrogate, the low surrogates follow the code points
+		return int(x[i] - y[i])
+	}
+	return len(x) - len(y)
+}
+
+// utf16Unit is the first UTF-16 code unit of r, supplementary characters
+// start with a high surrogate so they sort before U+E000-U+FFFF
+func utf16Unit(r rune) int {
+	if r >= 0x10000 {
+		return 0xD800 + int(r-0x10000)>>10
+	}
+	return int(r)
+}
@@ -19,3 +19,27 @@ func TestArray(t *testing.T) {
 	arr1.Reverse()
 	fmt.Println(arr1)
 }
+
+func TestSort(t *testing.T) {
+	t.Run("default order compares strings", func(t *testing.T) {
+		var arr JSArray[int] = []int{10, 9, 1, 100}
+		arr.Sort()
+		if fmt.Sprint(arr) != "[1 10 100 9]" {
+			t.Fatal(arr)
+		}
+	})
+	t.Run("utf-16 code units", func(t *testing.T) {
+		var arr JSArray[string] = []string{"Ａ", "😀", "b", "B"}
+		arr.Sort()
+		if fmt.Sprint(arr) != "[B b 😀 Ａ]" {
+			t.Fatal(arr)
+		}
+	})
+	t.Run("compare function and stability", func(t *testing.T) {
+		var arr JSArray[string] = []string{"bb", "a", "cc", "d"}
+		sorted := arr.ToSorted(func(a, b string) int { return len(a) - len(b) })
+		if fmt.Sprint(*sorted) != "[a d bb cc]" || fmt.Sprint(arr) != "[bb a cc d]" {
+			t.Fatal(*sorted, arr)
+		}
+	})
+}
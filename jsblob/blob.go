@@ -0,0 +1,186 @@
+package jsblob
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"d1y.io/jslike/jsencoding"
+	"d1y.io/jslike/jsstreams"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/Blob#options
+type BlobOptions struct {
+	// the MIME type, lower cased, "" when it has a character outside of
+	// U+0020 to U+007E
+	Type string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob
+//
+// A Blob is a list of byte ranges in memory or in files, concatenating or
+// slicing Blobs shares the ranges instead of copying the bytes. A Blob never
+// changes after it is made, so it is safe for concurrent use.
+type Blob struct {
+	segments []segment
+	size     int64
+	typ      string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/Blob
+//
+// parts are strings, []byte, *Blob or *File. Strings and []byte are copied,
+// the bytes of a Blob are shared.
+func NewBlob(parts []any, options ...BlobOptions) (*Blob, error) {
+	var opts BlobOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	b := &Blob{typ: normalizeType(opts.Type)}
+	// strings and []byte next to each other share one copy
+	var data []byte
+	flush := func() {
+		if len(data) > 0 {
+			b.append(memorySegment(data))
+			data = nil
+		}
+	}
+	for _, part := range parts {
+		switch v := part.(type) {
+		case string:
+			data = append(data, v...)
+		case []byte:
+			data = append(data, v...)
+		case *Blob:
+			flush()
+			b.append(v.segments...)
+		case *File:
+			flush()
+			b.append(v.segments...)
+		default:
+			return nil, fmt.Errorf("jsblob: cannot use type %T as a blob part", v)
+		}
+	}
+	flush()
+	return b, nil
+}
+
+func (b *Blob) append(segments ...segment) {
+	for _, s := range segments {
+		if s.length > 0 {
+			b.segments = append(b.segments, s)
+			b.size += s.length
+		}
+	}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/size
+func (b *Blob) Size() int64 {
+	return b.size
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/type
+func (b *Blob) Type() string {
+	return b.typ
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/slice
+//
+// A negative start or end counts from the end like in JS, pass Size() as end
+// to slice to the end. The bytes are shared with b.
+func (b *Blob) Slice(start, end int64, contentType string) *Blob {
+	start, end = relative(start, b.size), relative(end, b.size)
+	slice := &Blob{typ: normalizeType(contentType)}
+	var offset int64
+	for _, s := range b.segments {
+		from, to := max(start-offset, 0), min(end-offset, s.length)
+		if from < to {
+			slice.append(s.slice(from, to))
+		}
+		offset += s.length
+	}
+	return slice
+}
+
+func relative(index, size int64) int64 {
+	if index < 0 {
+		return max(size+index, 0)
+	}
+	return min(index, size)
+}
+
+// Reader reads the bytes of b, a file is opened when the reader gets to it.
+// Close closes the file being read.
+func (b *Blob) Reader() io.ReadCloser {
+	return &blobReader{segments: b.segments}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/stream
+func (b *Blob) Stream() *jsstreams.ReadableStream[[]byte] {
+	return jsstreams.FromReader(b.Reader())
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/text
+//
+// The bytes are decoded as UTF-8 like TextDecoder, a byte order mark is
+// removed and invalid bytes become U+FFFD
+func (b *Blob) Text() (string, error) {
+	decoder, _ := jsencoding.NewTextDecoder("utf-8")
+	r := b.Reader()
+	defer r.Close()
+	var text strings.Builder
+	text.Grow(int(b.size))
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 || err == io.EOF {
+			// a sequence split between reads is decoded with the next one
+			chunk, _ := decoder.Decode(buf[:n], jsencoding.DecodeOptions{Stream: err != io.EOF})
+			text.WriteString(chunk)
+		}
+		if err == io.EOF {
+			return text.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/arrayBuffer
+//
+// The bytes are a copy
+func (b *Blob) ArrayBuffer() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, b.size))
+	if _, err := b.writeTo(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Blob/bytes
+//
+// The bytes of a Blob in one piece of memory are returned without a copy,
+// they must not be changed.
+func (b *Blob) Bytes() ([]byte, error) {
+	if len(b.segments) == 1 && b.segments[0].file == nil {
+		return b.segments[0].data, nil
+	}
+	return b.ArrayBuffer()
+}
+
+func (b *Blob) writeTo(w io.Writer) (int64, error) {
+	r := b.Reader()
+	defer r.Close()
+	return io.Copy(w, r)
+}
+
+func normalizeType(typ string) string {
+	for i := 0; i < len(typ); i++ {
+		if typ[i] < 0x20 || typ[i] > 0x7e {
+			return ""
+		}
+	}
+	return strings.ToLower(typ)
+}
@@ -0,0 +1,169 @@
+package jsblob
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlob(t *testing.T) {
+	inner, _ := NewBlob([]any{"world"})
+	blob, err := NewBlob([]any{"hello ", []byte("go "), inner}, BlobOptions{Type: "Text/Plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := blob.Text(); text != "hello go world" || blob.Size() != 14 || blob.Type() != "text/plain" {
+		t.Fatal(text, blob.Size(), blob.Type())
+	}
+	if blob, _ := NewBlob(nil, BlobOptions{Type: "text/é"}); blob.Type() != "" || blob.Size() != 0 {
+		t.Fatal(blob.Type())
+	}
+	if _, err := NewBlob([]any{1}); err == nil {
+		t.Fatal("expect an error for an int part")
+	}
+}
+
+func TestBlobText(t *testing.T) {
+	cases := []struct {
+		parts  []any
+		expect string
+	}{
+		{[]any{"\ufeffbom"}, "bom"},
+		{[]any{[]byte{'a', 0xff, 'b'}}, "a\ufffdb"},
+		{[]any{[]byte{'a', 0xe4, 0xb8}}, "a\ufffd"},
+		// a character split between two parts
+		{[]any{[]byte{0xe4}, mustBlob(t, []byte{0xb8, 0xad})}, "中"},
+	}
+	for _, c := range cases {
+		blob := mustBlob(t, c.parts...)
+		if text, err := blob.Text(); err != nil || text != c.expect {
+			t.Fatalf("expect %q, got %q %v", c.expect, text, err)
+		}
+	}
+}
+
+func mustBlob(t *testing.T, parts ...any) *Blob {
+	blob, err := NewBlob(parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blob
+}
+
+func TestFile(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	file, err := NewFile([]any{"a,b"}, "a.csv", FileOptions{Type: "text/csv", LastModified: modified})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name() != "a.csv" || !file.LastModified().Equal(modified) || file.Type() != "text/csv" || file.Size() != 3 {
+		t.Fatal(file)
+	}
+	blob, _ := NewBlob([]any{file, "!"})
+	if text, _ := blob.Text(); text != "a,b!" {
+		t.Fatal(text)
+	}
+	if file, _ := NewFile(nil, "empty"); file.LastModified().IsZero() {
+		t.Fatal("expect now as the default last modified time")
+	}
+}
+
+func TestBlobSlice(t *testing.T) {
+	hello, _ := NewBlob([]any{"hello "})
+	blob, _ := NewBlob([]any{hello, "go ", hello.Slice(0, 5, "")})
+	cases := []struct {
+		start, end int64
+		text       string
+	}{
+		{0, blob.Size(), "hello go hello"},
+		{3, 11, "lo go he"},
+		{-5, -1, "hell"},
+		{-100, 2, "he"},
+		{9, 100, "hello"},
+		{5, 2, ""},
+	}
+	for _, c := range cases {
+		slice := blob.Slice(c.start, c.end, "Text/Plain")
+		if text, _ := slice.Text(); text != c.text || slice.Size() != int64(len(c.text)) || slice.Type() != "text/plain" {
+			t.Fatal(c, text)
+		}
+	}
+
+	data, _ := hello.Bytes()
+	if again, _ := hello.Bytes(); &data[0] != &again[0] {
+		t.Fatal("expect the bytes of a blob in memory to be shared")
+	}
+	if copied, _ := hello.ArrayBuffer(); &data[0] == &copied[0] {
+		t.Fatal("expect ArrayBuffer to copy")
+	}
+
+	reader, _ := blob.Stream().GetReader()
+	var read []byte
+	for {
+		chunk, done, err := reader.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			break
+		}
+		read = append(read, chunk...)
+	}
+	if string(read) != "hello go hello" {
+		t.Fatal(string(read))
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	os.WriteFile(path, []byte(`{"a":1}`), 0o644)
+	file, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name() != "data.json" || file.Type() != "application/json" || file.Size() != 7 {
+		t.Fatal(file.Name(), file.Type(), file.Size())
+	}
+	blob, _ := NewBlob([]any{"[", file.Slice(1, -1, ""), "]"})
+	if text, err := blob.Text(); text != `["a":1]` || err != nil {
+		t.Fatal(text, err)
+	}
+
+	os.WriteFile(path, []byte(`{"a":2,"b":3}`), 0o644)
+	if _, err := blob.Text(); err != ErrNotReadable {
+		t.Fatal(err)
+	}
+	if _, err := OpenFile(filepath.Dir(path)); err == nil {
+		t.Fatal("expect an error for a directory")
+	}
+}
+
+func TestFromReader(t *testing.T) {
+	defer func(limit int64) { maxMemory = limit }(maxMemory)
+	maxMemory = 4
+
+	for _, data := range []string{"tiny", "more than the limit"} {
+		blob, err := FromReader(strings.NewReader(data), BlobOptions{Type: "text/plain"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text, _ := blob.Slice(1, blob.Size(), "").Text(); text != data[1:] || blob.Type() != "text/plain" {
+			t.Fatal(text)
+		}
+		if onDisk := blob.segments[0].file != nil; onDisk != (len(data) > 4) {
+			t.Fatal(data, onDisk)
+		}
+		if err := blob.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// a second Close is fine, reading a closed file fails
+		if err := blob.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := blob.Text(); (err != nil) != (len(data) > 4) {
+			t.Fatal(data, err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package jsblob
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/File/File#options
+type FileOptions struct {
+	Type string
+	// the time the file was changed, now when it is zero
+	LastModified time.Time
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/File
+type File struct {
+	*Blob
+	name         string
+	lastModified time.Time
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/File/File
+//
+// parts are like the parts of NewBlob
+func NewFile(parts []any, name string, options ...FileOptions) (*File, error) {
+	var opts FileOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	blob, err := NewBlob(parts, BlobOptions{Type: opts.Type})
+	if err != nil {
+		return nil, err
+	}
+	if opts.LastModified.IsZero() {
+		opts.LastModified = time.Now()
+	}
+	return &File{Blob: blob, name: name, lastModified: opts.LastModified}, nil
+}
+
+// OpenFile is a File of the file at path like the File of an <input> in
+// browsers, the bytes are read from disk when the File is read. Type follows
+// the extension and LastModified is the time of the file by default, reading
+// fails with ErrNotReadable once the file changes.
+func OpenFile(path string, options ...FileOptions) (*File, error) {
+	var opts FileOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("jsblob: %s is not a regular file", path)
+	}
+	if opts.Type == "" {
+		opts.Type = mime.TypeByExtension(filepath.Ext(path))
+	}
+	if opts.LastModified.IsZero() {
+		opts.LastModified = info.ModTime()
+	}
+	blob, _ := NewBlob(nil, BlobOptions{Type: opts.Type})
+	blob.append(segment{file: &diskFile{path: path, size: info.Size(), modTime: info.ModTime()}, length: info.Size()})
+	return &File{Blob: blob, name: filepath.Base(path), lastModified: opts.LastModified}, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/File/name
+func (f *File) Name() string {
+	return f.name
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/File/lastModified
+func (f *File) LastModified() time.Time {
+	return f.lastModified
+}
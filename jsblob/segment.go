@@ -0,0 +1,159 @@
+package jsblob
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrNotReadable is the error of reading a Blob of a file that changed after
+// the Blob was made, like NotReadableError in JS
+var ErrNotReadable = errors.New("jsblob: the file changed after the blob was made")
+
+// maxMemory is how much of a reader FromReader keeps in memory before it
+// writes to a temporary file
+var maxMemory int64 = 32 << 20
+
+// a segment is a range of bytes in memory or in a file
+type segment struct {
+	data   []byte
+	file   *diskFile
+	offset int64
+	length int64
+}
+
+func memorySegment(data []byte) segment {
+	return segment{data: data, length: int64(len(data))}
+}
+
+func (s segment) slice(from, to int64) segment {
+	if s.file == nil {
+		return memorySegment(s.data[from:to])
+	}
+	return segment{file: s.file, offset: s.offset + from, length: to - from}
+}
+
+// open reads the segment, the closer is nil when there is nothing to close
+func (s segment) open() (io.Reader, io.Closer, error) {
+	switch {
+	case s.file == nil:
+		return bytes.NewReader(s.data), nil, nil
+	case s.file.handle != nil:
+		return io.NewSectionReader(s.file.handle, s.offset, s.length), nil, nil
+	}
+	f, err := os.Open(s.file.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if info.Size() != s.file.size || !info.ModTime().Equal(s.file.modTime) {
+		f.Close()
+		return nil, nil, ErrNotReadable
+	}
+	return io.NewSectionReader(f, s.offset, s.length), f, nil
+}
+
+// diskFile is a file on disk, a path is opened on every read and must not
+// change, a handle is an open temporary file nobody else can change
+type diskFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+	handle  *os.File
+}
+
+type blobReader struct {
+	segments []segment
+	reader   io.Reader
+	closer   io.Closer
+	err      error
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	for r.err == nil {
+		if r.reader == nil {
+			if len(r.segments) == 0 {
+				return 0, io.EOF
+			}
+			r.reader, r.closer, r.err = r.segments[0].open()
+			r.segments = r.segments[1:]
+			continue
+		}
+		n, err := r.reader.Read(p)
+		if err == io.EOF {
+			r.closeSegment()
+			err = nil
+		}
+		if n > 0 || err != nil {
+			r.err = err
+			return n, err
+		}
+	}
+	return 0, r.err
+}
+
+func (r *blobReader) closeSegment() {
+	if r.closer != nil {
+		r.closer.Close()
+	}
+	r.reader, r.closer = nil, nil
+}
+
+func (r *blobReader) Close() error {
+	r.closeSegment()
+	r.segments = nil
+	return nil
+}
+
+// FromReader reads r into a Blob, what is over 32MB is written to a
+// temporary file that is removed right away, so a large body does not stay
+// in memory. Close frees the file, else the garbage collector closes it.
+func FromReader(r io.Reader, options ...BlobOptions) (*Blob, error) {
+	b, _ := NewBlob(nil, options...)
+	var buf bytes.Buffer
+	_, err := io.CopyN(&buf, r, maxMemory+1)
+	if err == io.EOF {
+		b.append(memorySegment(buf.Bytes()))
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", "jsblob-*")
+	if err != nil {
+		return nil, err
+	}
+	// the open file keeps the data until Close or the garbage collector
+	// closes it, a system that can't remove an open file leaves it in the
+	// temporary directory
+	os.Remove(f.Name())
+	rest, err := io.Copy(f, io.MultiReader(&buf, r))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	b.append(segment{file: &diskFile{handle: f}, length: rest})
+	return b, nil
+}
+
+// Close closes the temporary files FromReader made for b, which frees their
+// disk space. Blobs sharing the bytes, like slices of b, fail to read the
+// files after. Bytes in memory or in files opened by path need no Close.
+func (b *Blob) Close() error {
+	var err error
+	for _, s := range b.segments {
+		if s.file == nil || s.file.handle == nil {
+			continue
+		}
+		if closeErr := s.file.handle.Close(); closeErr != nil && !errors.Is(closeErr, os.ErrClosed) && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,189 @@
+package jsencoding
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// https://encoding.spec.whatwg.org/#utf-8-decoder
+type utf8Decoder struct {
+	fatal   bool
+	pending []byte
+}
+
+func (d *utf8Decoder) decode(b *strings.Builder, p []byte, flush bool) error {
+	data := p
+	if len(d.pending) > 0 {
+		data = append(d.pending, p...)
+		d.pending = nil
+	}
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r != utf8.RuneError || size > 1 {
+			b.Write(data[i : i+size])
+			i += size
+			continue
+		}
+		n := validPrefix(data[i:])
+		if n > 0 && i+n == len(data) && !flush {
+			d.pending = append([]byte(nil), data[i:]...)
+			break
+		}
+		if d.fatal {
+			return ErrInvalidData
+		}
+		b.WriteRune(utf8.RuneError)
+		i += max(n, 1)
+	}
+	return nil
+}
+
+func (d *utf8Decoder) reset() {
+	d.pending = nil
+}
+
+// validPrefix is the length of the start of a rune at the start of p, 0 when
+// p[0] can't start one. The bytes of a cut rune are one U+FFFD.
+func validPrefix(p []byte) int {
+	lead := p[0]
+	var need int
+	lower, upper := byte(0x80), byte(0xBF)
+	switch {
+	case 0xC2 <= lead && lead <= 0xDF:
+		need = 1
+	case 0xE0 <= lead && lead <= 0xEF:
+		need = 2
+		if lead == 0xE0 {
+			lower = 0xA0
+		} else if lead == 0xED {
+			upper = 0x9F
+		}
+	case 0xF0 <= lead && lead <= 0xF4:
+		need = 3
+		if lead == 0xF0 {
+			lower = 0x90
+		} else if lead == 0xF4 {
+			upper = 0x8F
+		}
+	default:
+		return 0
+	}
+	n := 1
+	for ; n <= need && n < len(p); n++ {
+		if p[n] < lower || p[n] > upper {
+			break
+		}
+		lower, upper = 0x80, 0xBF
+	}
+	return n
+}
+
+// https://encoding.spec.whatwg.org/#shared-utf-16-decoder
+type utf16Decoder struct {
+	bigEndian bool
+	fatal     bool
+	// an odd byte at the end of the last chunk
+	pending []byte
+	// a high surrogate waiting for its pair, 0 when there is none
+	lead rune
+}
+
+func (d *utf16Decoder) decode(b *strings.Builder, p []byte, flush bool) error {
+	data := p
+	if len(d.pending) > 0 {
+		data = append(d.pending, p...)
+		d.pending = nil
+	}
+	i := 0
+	for ; i+1 < len(data); i += 2 {
+		unit := rune(data[i])<<8 | rune(data[i+1])
+		if !d.bigEndian {
+			unit = rune(data[i+1])<<8 | rune(data[i])
+		}
+		if d.lead != 0 {
+			lead := d.lead
+			d.lead = 0
+			if 0xDC00 <= unit && unit <= 0xDFFF {
+				b.WriteRune(0x10000 + (lead-0xD800)<<10 + (unit - 0xDC00))
+				continue
+			}
+			// the unit after a lone high surrogate is decoded on its own
+			if d.fatal {
+				return ErrInvalidData
+			}
+			b.WriteRune(utf8.RuneError)
+		}
+		switch {
+		case 0xD800 <= unit && unit <= 0xDBFF:
+			d.lead = unit
+		case 0xDC00 <= unit && unit <= 0xDFFF:
+			if d.fatal {
+				return ErrInvalidData
+			}
+			b.WriteRune(utf8.RuneError)
+		default:
+			b.WriteRune(unit)
+		}
+	}
+	if i < len(data) {
+		d.pending = []byte{data[i]}
+	}
+	if flush && (d.pending != nil || d.lead != 0) {
+		d.reset()
+		if d.fatal {
+			return ErrInvalidData
+		}
+		b.WriteRune(utf8.RuneError)
+	}
+	return nil
+}
+
+func (d *utf16Decoder) reset() {
+	d.pending = nil
+	d.lead = 0
+}
+
+// legacyDecoder decodes the other encodings with golang.org/x/text, which
+// writes U+FFFD for invalid data
+type legacyDecoder struct {
+	transformer transform.Transformer
+	fatal       bool
+	pending     []byte
+}
+
+var replacement = []byte("\ufffd")
+
+func (d *legacyDecoder) decode(b *strings.Builder, p []byte, flush bool) error {
+	src := p
+	if len(d.pending) > 0 {
+		src = append(d.pending, p...)
+		d.pending = nil
+	}
+	var buf [4096]byte
+	for {
+		nDst, nSrc, err := d.transformer.Transform(buf[:], src, flush)
+		if d.fatal && bytes.Contains(buf[:nDst], replacement) {
+			return ErrInvalidData
+		}
+		b.Write(buf[:nDst])
+		src = src[nSrc:]
+		switch err {
+		case nil:
+			return nil
+		case transform.ErrShortDst:
+			continue
+		case transform.ErrShortSrc:
+			d.pending = append([]byte(nil), src...)
+			return nil
+		}
+		return err
+	}
+}
+
+func (d *legacyDecoder) reset() {
+	d.pending = nil
+	d.transformer.Reset()
+}
@@ -0,0 +1,201 @@
+package jsencoding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ErrInvalidData is the error of a fatal TextDecoder on data that is not
+// valid for its encoding
+var ErrInvalidData = errors.New("jsencoding: the encoded data is not valid")
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextEncoder
+//
+// It always encodes to UTF-8 like in JS
+type TextEncoder struct{}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextEncoder/TextEncoder
+func NewTextEncoder() *TextEncoder {
+	return &TextEncoder{}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextEncoder/encoding
+func (e *TextEncoder) Encoding() string {
+	return "utf-8"
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextEncoder/encode
+//
+// Invalid UTF-8 in input becomes U+FFFD, like a lone surrogate in JS
+func (e *TextEncoder) Encode(input string) []byte {
+	if utf8.ValidString(input) {
+		return []byte(input)
+	}
+	var b strings.Builder
+	(&utf8Decoder{}).decode(&b, []byte(input), true)
+	return []byte(b.String())
+}
+
+// EncodeIntoResult is the result of EncodeInto
+type EncodeIntoResult struct {
+	// the bytes of source read, JS counts UTF-16 code units
+	Read int
+	// the bytes written to the destination
+	Written int
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextEncoder/encodeInto
+//
+// Only whole runes are written
+func (e *TextEncoder) EncodeInto(source string, destination []byte) EncodeIntoResult {
+	var result EncodeIntoResult
+	for result.Read < len(source) {
+		r, size := utf8.DecodeRuneInString(source[result.Read:])
+		if r == utf8.RuneError && size == 1 {
+			size = max(validPrefix([]byte(source[result.Read:])), 1)
+		}
+		if result.Written+utf8.RuneLen(r) > len(destination) {
+			break
+		}
+		result.Written += utf8.EncodeRune(destination[result.Written:], r)
+		result.Read += size
+	}
+	return result
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder/TextDecoder#options
+type TextDecoderOptions struct {
+	// returns ErrInvalidData on invalid data instead of writing U+FFFD
+	Fatal bool
+	// keeps a leading byte order mark in the text
+	IgnoreBOM bool
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder/decode#options
+type DecodeOptions struct {
+	// keeps an unfinished sequence at the end of input for the next Decode
+	Stream bool
+}
+
+// a decoder appends the text of p to b, it keeps an unfinished sequence at
+// the end of p unless flush
+type decoder interface {
+	decode(b *strings.Builder, p []byte, flush bool) error
+	reset()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder
+//
+// A TextDecoder keeps the state of a stream between Decode calls, it is not
+// safe for concurrent use.
+type TextDecoder struct {
+	encoding  string
+	fatal     bool
+	ignoreBOM bool
+	bomSeen   bool
+	decoder   decoder
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder/TextDecoder
+//
+// label is any label of the Encoding Standard like "utf-8", "latin1", "gbk"
+// or "shift_jis", "" is UTF-8.
+func NewTextDecoder(label string, options ...TextDecoderOptions) (*TextDecoder, error) {
+	var opts TextDecoderOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if label == "" {
+		label = "utf-8"
+	}
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("jsencoding: unknown encoding label %q", label)
+	}
+	name, err := htmlindex.Name(enc)
+	if err != nil || name == "replacement" {
+		return nil, fmt.Errorf("jsencoding: unsupported encoding %q", label)
+	}
+	d := &TextDecoder{encoding: name, fatal: opts.Fatal, ignoreBOM: opts.IgnoreBOM}
+	switch name {
+	case "utf-8":
+		d.decoder = &utf8Decoder{fatal: opts.Fatal}
+	case "utf-16le", "utf-16be":
+		d.decoder = &utf16Decoder{bigEndian: name == "utf-16be", fatal: opts.Fatal}
+	default:
+		d.decoder = &legacyDecoder{transformer: enc.NewDecoder(), fatal: opts.Fatal}
+	}
+	return d, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder/encoding
+//
+// The name of the encoding, like "windows-1252" for the label "latin1"
+func (d *TextDecoder) Encoding() string {
+	return d.encoding
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder/fatal
+func (d *TextDecoder) Fatal() bool {
+	return d.fatal
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder/ignoreBOM
+func (d *TextDecoder) IgnoreBOM() bool {
+	return d.ignoreBOM
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoder/decode
+func (d *TextDecoder) Decode(input []byte, options ...DecodeOptions) (string, error) {
+	stream := len(options) > 0 && options[0].Stream
+	var b strings.Builder
+	b.Grow(len(input))
+	if err := d.decoder.decode(&b, input, !stream); err != nil {
+		d.reset()
+		return "", err
+	}
+	text := b.String()
+	if !d.bomSeen && text != "" {
+		d.bomSeen = true
+		// only the UTF decoders remove a byte order mark
+		if !d.ignoreBOM && strings.HasPrefix(d.encoding, "utf-") {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+	}
+	if !stream {
+		d.reset()
+	}
+	return text, nil
+}
+
+func (d *TextDecoder) reset() {
+	d.bomSeen = false
+	d.decoder.reset()
+}
+
+// https://encoding.spec.whatwg.org/#decode
+//
+// Decode decodes input as a browser decodes a document, a byte order mark
+// picks UTF-8 or UTF-16 over label and an unknown label falls back to UTF-8.
+func Decode(input []byte, label string) string {
+	switch {
+	case bytes.HasPrefix(input, []byte{0xEF, 0xBB, 0xBF}):
+		label = "utf-8"
+	case bytes.HasPrefix(input, []byte{0xFE, 0xFF}):
+		label = "utf-16be"
+	case bytes.HasPrefix(input, []byte{0xFF, 0xFE}):
+		label = "utf-16le"
+	}
+	decoder, err := NewTextDecoder(label)
+	if err != nil {
+		decoder, _ = NewTextDecoder("utf-8")
+	}
+	// not fatal, it can't fail
+	text, _ := decoder.Decode(input)
+	return text
+}
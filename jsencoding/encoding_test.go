@@ -0,0 +1,103 @@
+package jsencoding
+
+import (
+	"testing"
+)
+
+func TestTextDecoder(t *testing.T) {
+	cases := []struct {
+		label    string
+		encoding string
+		input    string
+		text     string
+	}{
+		{"", "utf-8", "\xef\xbb\xbfjs€", "js€"},
+		{"UTF8", "utf-8", "a\xe2\x82b\xff", "a�b�"},
+		{"utf-16", "utf-16le", "\xff\xfea\x00=\xd8\x00\xde", "a😀"},
+		{"utf-16be", "utf-16be", "\x00a\xd8\x3d", "a�"},
+		{"latin1", "windows-1252", "caf\xe9 \x80", "café €"},
+		{"iso-8859-2", "iso-8859-2", "\xa3\xf3d\xbc\xd8", "ŁódźŘ"},
+		{"shift_jis", "shift_jis", "\x93\xfa\x96\x7b\x8c\xea", "日本語"},
+		{"euc-kr", "euc-kr", "\xc7\xd1\xb1\xb9\xbe\xee", "한국어"},
+		{"gb2312", "gbk", "\xd6\xd0\xce\xc4", "中文"},
+		{"gb18030", "gb18030", "\xd6\xd0\xce\xc4\x94\x39\xfc\x36", "中文😀"},
+	}
+	for _, c := range cases {
+		decoder, err := NewTextDecoder(c.label)
+		if err != nil {
+			t.Fatal(c.label, err)
+		}
+		if decoder.Encoding() != c.encoding {
+			t.Fatal(c.label, decoder.Encoding())
+		}
+		if text, err := decoder.Decode([]byte(c.input)); err != nil || text != c.text {
+			t.Fatalf("%s: expect %q, got %q %v", c.label, c.text, text, err)
+		}
+
+		// byte by byte as a stream
+		text := ""
+		for i := 0; i < len(c.input); i++ {
+			chunk, err := decoder.Decode([]byte(c.input[i:i+1]), DecodeOptions{Stream: true})
+			if err != nil {
+				t.Fatal(c.label, err)
+			}
+			text += chunk
+		}
+		if end, _ := decoder.Decode(nil); text+end != c.text {
+			t.Fatalf("%s: expect %q when streaming, got %q", c.label, c.text, text+end)
+		}
+	}
+
+	for _, label := range []string{"bogus", "iso-2022-kr"} {
+		if _, err := NewTextDecoder(label); err == nil {
+			t.Fatal("expect an error for", label)
+		}
+	}
+}
+
+func TestTextDecoderOptions(t *testing.T) {
+	decoder, _ := NewTextDecoder("utf-8", TextDecoderOptions{IgnoreBOM: true})
+	if text, _ := decoder.Decode([]byte("\xef\xbb\xbfa")); text != "\ufeffa" {
+		t.Fatalf("%q", text)
+	}
+
+	for _, label := range []string{"utf-8", "utf-16le", "gbk"} {
+		decoder, _ = NewTextDecoder(label, TextDecoderOptions{Fatal: true})
+		if _, err := decoder.Decode([]byte{0xff}); err != ErrInvalidData {
+			t.Fatal(label, err)
+		}
+	}
+	decoder, _ = NewTextDecoder("utf-8", TextDecoderOptions{Fatal: true})
+	if text, err := decoder.Decode([]byte{0xe4, 0xb8}, DecodeOptions{Stream: true}); text != "" || err != nil {
+		t.Fatal(text, err)
+	}
+	if _, err := decoder.Decode(nil); err != ErrInvalidData {
+		t.Fatal("expect an unfinished rune to fail at the end", err)
+	}
+}
+
+func TestTextEncoder(t *testing.T) {
+	encoder := NewTextEncoder()
+	if encoded := encoder.Encode("a€\xff"); string(encoded) != "a€�" {
+		t.Fatalf("%q", encoded)
+	}
+	dest := make([]byte, 5)
+	if result := encoder.EncodeInto("a€b", dest); result.Read != 5 || result.Written != 5 || string(dest) != "a€b" {
+		t.Fatal(result)
+	}
+	if result := encoder.EncodeInto("a€b", dest[:3]); result.Read != 1 || result.Written != 1 {
+		t.Fatal(result)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if text := Decode([]byte("\xd6\xd0\xce\xc4"), "gbk"); text != "中文" {
+		t.Fatal(text)
+	}
+	if text := Decode([]byte("\xef\xbb\xbf中文"), "gbk"); text != "中文" {
+		t.Fatal(text)
+	}
+	if text := Decode([]byte("中文"), "bogus"); text != "中文" {
+		t.Fatal(text)
+	}
+}
@@ -0,0 +1,75 @@
+package jsfetch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"d1y.io/jslike/jsblob"
+	"d1y.io/jslike/jsfetch/multipart"
+	"d1y.io/jslike/jsstreams"
+)
+
+// https://fetch.spec.whatwg.org/#concept-bodyinit-extract
+type extractedBody struct {
+	reader io.Reader
+	// the stream of a ReadableStream body
+	stream      *jsstreams.ReadableStream[[]byte]
+	contentType string
+	// -1 when it is not known
+	length int64
+}
+
+// extractBody reads the body of Options or NewResponse, a nil body has a nil
+// reader
+func extractBody(body any) (extractedBody, error) {
+	extracted := extractedBody{length: -1}
+	switch v := body.(type) {
+	case nil:
+		extracted.length = 0
+	case string:
+		extracted.reader, extracted.length = strings.NewReader(v), int64(len(v))
+		extracted.contentType = "text/plain;charset=UTF-8"
+	case []byte:
+		extracted.reader, extracted.length = bytes.NewReader(v), int64(len(v))
+	case *jsblob.Blob:
+		extracted.reader, extracted.length = v.Reader(), v.Size()
+		extracted.contentType = v.Type()
+	case *jsblob.File:
+		return extractBody(v.Blob)
+	case url.Values:
+		encoded := v.Encode()
+		extracted.reader, extracted.length = strings.NewReader(encoded), int64(len(encoded))
+		extracted.contentType = "application/x-www-form-urlencoded;charset=UTF-8"
+	case *multipart.FormData:
+		extracted.reader, extracted.length = v.Body(), v.Size()
+		extracted.contentType = v.FormDataContentType()
+	case *jsstreams.ReadableStream[[]byte]:
+		if v.Locked() || v.Disturbed() {
+			return extracted, fmt.Errorf("jsfetch: body stream is locked or disturbed")
+		}
+		extracted.reader, extracted.stream = jsstreams.ToReader(v), v
+	case io.Reader:
+		extracted.reader = v
+		if length, ok := bodyLength(v); ok {
+			extracted.length = length
+		}
+	default:
+		return extracted, fmt.Errorf("jsfetch: cannot use type %T as a body", v)
+	}
+	return extracted, nil
+}
+
+func bodyLength(body io.Reader) (int64, bool) {
+	switch v := body.(type) {
+	case *bytes.Buffer:
+		return int64(v.Len()), true
+	case *bytes.Reader:
+		return int64(v.Len()), true
+	case *strings.Reader:
+		return int64(v.Len()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+package jsfetch
+
+import "net/http"
+
+// Fetcher sends a Request, the next step of an Interceptor
+type Fetcher func(req *Request) (*Response, error)
+
+// Interceptor wraps every Fetch of a Client, it can change req before calling
+// next, look at the Response after, or answer without calling next at all.
+type Interceptor func(req *Request, next Fetcher) (*Response, error)
+
+// Client is Fetch with its own http.Client and Interceptors, it is safe for
+// concurrent use
+type Client struct {
+	httpClient   *http.Client
+	interceptors []Interceptor
+}
+
+// A ClientOption configures NewClient
+type ClientOption func(c *Client)
+
+// WithHTTPClient sends the requests with client instead of http.DefaultClient,
+// for timeouts, proxies, TLS config, a cookie jar or tracing
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithInterceptors adds interceptors, the first one sees the Request first
+// and the Response last
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+func NewClient(options ...ClientOption) *Client {
+	c := &Client{}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// DefaultClient is the Client of the package level Fetch
+var DefaultClient = NewClient()
+
+// Fetch is the package level Fetch through the Interceptors of c
+func (c *Client) Fetch(input any, opts ...Options) (*Response, error) {
+	if _, ok := input.(*Request); !ok && len(opts) == 0 {
+		opts = []Options{DefaultOptions}
+	}
+
+	req, err := NewRequest(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher := Fetcher(func(req *Request) (*Response, error) {
+		client := c.httpClient
+		if client == nil {
+			client = http.DefaultClient
+		}
+		return req.do(client)
+	})
+	for idx := len(c.interceptors) - 1; idx >= 0; idx-- {
+		interceptor, next := c.interceptors[idx], fetcher
+		fetcher = func(req *Request) (*Response, error) {
+			return interceptor(req, next)
+		}
+	}
+	return fetcher(req)
+}
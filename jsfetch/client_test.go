@@ -0,0 +1,75 @@
+package jsfetch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(time.Millisecond * 100)
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+		}
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+		w.Header().Set("X-Request-Id", r.Header.Get("X-Request-Id"))
+		if cookie, err := r.Cookie("session"); err == nil {
+			w.Header().Set("X-Session", cookie.Value)
+		}
+	}))
+	defer server.Close()
+
+	var log []string
+	jar, _ := cookiejar.New(nil)
+	client := NewClient(
+		WithHTTPClient(&http.Client{Timeout: time.Millisecond * 50, Jar: jar}),
+		WithInterceptors(
+			func(req *Request, next Fetcher) (*Response, error) {
+				log = append(log, "log "+req.URL)
+				res, err := next(req)
+				log = append(log, "done")
+				return res, err
+			},
+			func(req *Request, next Fetcher) (*Response, error) {
+				req.Headers.Set("Authorization", "Bearer token")
+				req.Headers.Set("X-Request-Id", "42")
+				return next(req)
+			},
+		),
+	)
+	res, err := client.Fetch(server.URL + "/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Header.Get("X-Authorization") != "Bearer token" || res.Header.Get("X-Request-Id") != "42" {
+		t.Fatal(res.Header)
+	}
+	if len(log) != 2 || log[0] != "log "+server.URL+"/login" || log[1] != "done" {
+		t.Fatal(log)
+	}
+
+	if res, err := client.Fetch(server.URL); err != nil || res.Header.Get("X-Session") != "1" {
+		t.Fatal(res.Header, err)
+	}
+	if res, err := client.Fetch(server.URL, Options{Credentials: "omit"}); err != nil || res.Header.Get("X-Session") != "" {
+		t.Fatal(res.Header, err)
+	}
+
+	if _, err := client.Fetch(server.URL + "/slow"); err == nil {
+		t.Fatal("expect the timeout of the http.Client")
+	}
+
+	sentinel := errors.New("sentinel")
+	cached := NewClient(WithInterceptors(func(req *Request, next Fetcher) (*Response, error) {
+		return nil, sentinel
+	}))
+	if _, err := cached.Fetch(server.URL); err != sentinel {
+		t.Fatal(err)
+	}
+}
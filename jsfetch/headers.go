@@ -0,0 +1,380 @@
+package jsfetch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// https://fetch.spec.whatwg.org/#concept-headers-guard
+type Guard string
+
+const (
+	GuardNone Guard = "none"
+	// drops the forbidden request headers, like the headers of a Request
+	GuardRequest Guard = "request"
+	// drops Set-Cookie and Set-Cookie2
+	GuardResponse Guard = "response"
+	// refuses every change, like the headers of a fetched Response
+	GuardImmutable Guard = "immutable"
+)
+
+// ErrImmutableHeaders is returned when changing Headers with GuardImmutable
+var ErrImmutableHeaders = errors.New("jsfetch: headers are immutable")
+
+type headerEntry struct {
+	// lower case
+	name  string
+	value string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers
+//
+// Names are case-insensitive and values are trimmed of HTTP whitespace like
+// the Fetch spec says, Header converts to http.Header with canonical keys.
+// Headers is not safe for concurrent changes.
+type Headers struct {
+	list  []headerEntry
+	guard Guard
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/Headers
+//
+// init is an http.Header, a map[string]string, a [][2]string of pairs or
+// another *Headers
+func NewHeaders(init ...any) (*Headers, error) {
+	return NewHeadersWithGuard(GuardNone, init...)
+}
+
+// NewHeadersWithGuard is NewHeaders with the rules of guard, an immutable
+// Headers still takes init
+func NewHeadersWithGuard(guard Guard, init ...any) (*Headers, error) {
+	h := &Headers{guard: guard}
+	if guard == GuardImmutable {
+		h.guard = GuardNone
+	}
+	for _, value := range init {
+		if err := h.fill(value); err != nil {
+			return nil, err
+		}
+	}
+	h.guard = guard
+	return h, nil
+}
+
+func (h *Headers) fill(init any) error {
+	switch v := init.(type) {
+	case nil:
+		return nil
+	case http.Header:
+		return h.fillHeader(v)
+	case map[string][]string:
+		return h.fillHeader(v)
+	case map[string]string:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := h.Append(name, v[name]); err != nil {
+				return err
+			}
+		}
+	case [][2]string:
+		for _, pair := range v {
+			if err := h.Append(pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+	case *Headers:
+		if v == nil {
+			return nil
+		}
+		for _, entry := range v.list {
+			if err := h.Append(entry.name, entry.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("jsfetch: cannot init headers with type %T", v)
+	}
+	return nil
+}
+
+func (h *Headers) fillHeader(header map[string][]string) error {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			if err := h.Append(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/append
+func (h *Headers) Append(name, value string) error {
+	value = normalizeHeaderValue(value)
+	if err := validateHeader(name, value); err != nil {
+		return err
+	}
+	if ignore, err := h.guarded(name, value); ignore || err != nil {
+		return err
+	}
+	h.list = append(h.list, headerEntry{name: strings.ToLower(name), value: value})
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/set
+func (h *Headers) Set(name, value string) error {
+	value = normalizeHeaderValue(value)
+	if err := validateHeader(name, value); err != nil {
+		return err
+	}
+	if ignore, err := h.guarded(name, value); ignore || err != nil {
+		return err
+	}
+	name = strings.ToLower(name)
+	list := h.list[:0]
+	found := false
+	for _, entry := range h.list {
+		if entry.name != name {
+			list = append(list, entry)
+		} else if !found {
+			// the first one keeps its place
+			found = true
+			list = append(list, headerEntry{name: name, value: value})
+		}
+	}
+	if !found {
+		list = append(list, headerEntry{name: name, value: value})
+	}
+	h.list = list
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/delete
+func (h *Headers) Delete(name string) error {
+	if !isToken(name) {
+		return fmt.Errorf("jsfetch: invalid header name %q", name)
+	}
+	if ignore, err := h.guarded(name, ""); ignore || err != nil {
+		return err
+	}
+	name = strings.ToLower(name)
+	list := h.list[:0]
+	for _, entry := range h.list {
+		if entry.name != name {
+			list = append(list, entry)
+		}
+	}
+	h.list = list
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/get
+//
+// The values of name joined with ", ", "" when there are none, use Has to
+// tell that apart from an empty value
+func (h *Headers) Get(name string) string {
+	name = strings.ToLower(name)
+	var values []string
+	for _, entry := range h.list {
+		if entry.name == name {
+			values = append(values, entry.value)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/has
+func (h *Headers) Has(name string) bool {
+	name = strings.ToLower(name)
+	for _, entry := range h.list {
+		if entry.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/getSetCookie
+func (h *Headers) GetSetCookie() []string {
+	values := []string{}
+	for _, entry := range h.list {
+		if entry.name == "set-cookie" {
+			values = append(values, entry.value)
+		}
+	}
+	return values
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/entries
+//
+// Sorted by name with the values of a name combined, except Set-Cookie which
+// has a pair for every value
+func (h *Headers) Entries() [][2]string {
+	names := make([]string, 0, len(h.list))
+	seen := make(map[string]bool, len(h.list))
+	for _, entry := range h.list {
+		if !seen[entry.name] {
+			seen[entry.name] = true
+			names = append(names, entry.name)
+		}
+	}
+	sort.Strings(names)
+	entries := make([][2]string, 0, len(names))
+	for _, name := range names {
+		if name == "set-cookie" {
+			for _, value := range h.GetSetCookie() {
+				entries = append(entries, [2]string{name, value})
+			}
+			continue
+		}
+		entries = append(entries, [2]string{name, h.Get(name)})
+	}
+	return entries
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/keys
+func (h *Headers) Keys() []string {
+	entries := h.Entries()
+	keys := make([]string, len(entries))
+	for idx, entry := range entries {
+		keys[idx] = entry[0]
+	}
+	return keys
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/values
+func (h *Headers) Values() []string {
+	entries := h.Entries()
+	values := make([]string, len(entries))
+	for idx, entry := range entries {
+		values[idx] = entry[1]
+	}
+	return values
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Headers/forEach
+func (h *Headers) ForEach(fn func(value, name string)) {
+	for _, entry := range h.Entries() {
+		fn(entry[1], entry[0])
+	}
+}
+
+func (h *Headers) Guard() Guard {
+	return h.guard
+}
+
+// Header returns the headers as an http.Header with canonical keys, every
+// value of a name stays a separate value
+func (h *Headers) Header() http.Header {
+	header := make(http.Header, len(h.list))
+	for _, entry := range h.list {
+		header.Add(entry.name, entry.value)
+	}
+	return header
+}
+
+// guarded reports whether the guard ignores the change of name, or refuses it
+// https://fetch.spec.whatwg.org/#headers-validate
+func (h *Headers) guarded(name, value string) (bool, error) {
+	switch h.guard {
+	case GuardImmutable:
+		return true, ErrImmutableHeaders
+	case GuardRequest:
+		return IsForbiddenRequestHeader(name, value), nil
+	case GuardResponse:
+		return IsForbiddenResponseHeader(name), nil
+	}
+	return false, nil
+}
+
+var forbiddenRequestHeaders = map[string]bool{
+	"accept-charset":                 true,
+	"accept-encoding":                true,
+	"access-control-request-headers": true,
+	"access-control-request-method":  true,
+	"connection":                     true,
+	"content-length":                 true,
+	"cookie":                         true,
+	"cookie2":                        true,
+	"date":                           true,
+	"dnt":                            true,
+	"expect":                         true,
+	"host":                           true,
+	"keep-alive":                     true,
+	"origin":                         true,
+	"referer":                        true,
+	"set-cookie":                     true,
+	"te":                             true,
+	"trailer":                        true,
+	"transfer-encoding":              true,
+	"upgrade":                        true,
+	"via":                            true,
+}
+
+// https://fetch.spec.whatwg.org/#forbidden-request-header
+func IsForbiddenRequestHeader(name, value string) bool {
+	name = strings.ToLower(name)
+	if forbiddenRequestHeaders[name] || strings.HasPrefix(name, "proxy-") || strings.HasPrefix(name, "sec-") {
+		return true
+	}
+	switch name {
+	case "x-http-method", "x-http-method-override", "x-method-override":
+		for _, method := range strings.Split(value, ",") {
+			switch strings.ToUpper(strings.Trim(method, " \t")) {
+			case "CONNECT", "TRACE", "TRACK":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// https://fetch.spec.whatwg.org/#forbidden-response-header-name
+func IsForbiddenResponseHeader(name string) bool {
+	name = strings.ToLower(name)
+	return name == "set-cookie" || name == "set-cookie2"
+}
+
+// https://fetch.spec.whatwg.org/#concept-header-value-normalize
+func normalizeHeaderValue(value string) string {
+	return strings.Trim(value, " \t\r\n")
+}
+
+func validateHeader(name, value string) error {
+	if !isToken(name) {
+		return fmt.Errorf("jsfetch: invalid header name %q", name)
+	}
+	if strings.ContainsAny(value, "\x00\r\n") {
+		return fmt.Errorf("jsfetch: invalid value for header %q", name)
+	}
+	return nil
+}
+
+// https://httpwg.org/specs/rfc9110.html#tokens
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package jsfetch
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestHeaders(t *testing.T) {
+	h, err := NewHeaders(map[string]string{"content-type": " text/plain\t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Append("Accept", "text/html")
+	h.Append("accept", "application/json")
+	h.Append("Set-Cookie", "a=1")
+	h.Append("set-cookie", "b=2")
+	if got := h.Get("ACCEPT"); got != "text/html, application/json" {
+		t.Fatal(got)
+	}
+	if got := h.Get("Content-Type"); got != "text/plain" {
+		t.Fatal(got)
+	}
+	if got := h.GetSetCookie(); !reflect.DeepEqual(got, []string{"a=1", "b=2"}) {
+		t.Fatal(got)
+	}
+	want := [][2]string{
+		{"accept", "text/html, application/json"},
+		{"content-type", "text/plain"},
+		{"set-cookie", "a=1"},
+		{"set-cookie", "b=2"},
+	}
+	if got := h.Entries(); !reflect.DeepEqual(got, want) {
+		t.Fatal(got)
+	}
+
+	h.Set("ACCEPT", "*/*")
+	h.Delete("set-cookie")
+	if h.Has("Set-Cookie") || h.Get("accept") != "*/*" {
+		t.Fatal(h.Entries())
+	}
+	if got := h.Header(); !reflect.DeepEqual(got, http.Header{"Accept": {"*/*"}, "Content-Type": {"text/plain"}}) {
+		t.Fatal(got)
+	}
+
+	cases := []struct{ name, value string }{
+		{"", "value"},
+		{"bad name", "value"},
+		{"x-header", "line\nbreak"},
+		{"x-header", "nul\x00"},
+	}
+	for _, c := range cases {
+		if err := h.Append(c.name, c.value); err == nil {
+			t.Fatal(c)
+		}
+	}
+}
+
+func TestHeadersGuard(t *testing.T) {
+	request, _ := NewHeadersWithGuard(GuardRequest)
+	request.Append("Cookie", "a=1")
+	request.Append("Sec-Fetch-Mode", "cors")
+	request.Append("X-HTTP-Method-Override", "GET, TRACE")
+	request.Append("X-Custom", "1")
+	if got := request.Keys(); !reflect.DeepEqual(got, []string{"x-custom"}) {
+		t.Fatal(got)
+	}
+
+	response, _ := NewHeadersWithGuard(GuardResponse, [][2]string{{"Set-Cookie", "a=1"}, {"Server", "go"}})
+	if response.Has("set-cookie") || !response.Has("server") {
+		t.Fatal(response.Entries())
+	}
+
+	immutable, _ := NewHeadersWithGuard(GuardImmutable, http.Header{"Server": {"go"}})
+	if err := immutable.Set("Server", "js"); !errors.Is(err, ErrImmutableHeaders) {
+		t.Fatal(err)
+	}
+	if immutable.Get("server") != "go" {
+		t.Fatal(immutable.Entries())
+	}
+}
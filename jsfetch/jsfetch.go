@@ -5,44 +5,66 @@ package jsfetch
 import (
 	"bytes"
 	"encoding/json"
-	"io"
 	"net/http"
+
+	"d1y.io/jslike/jspromise"
 )
 
+// https://developer.mozilla.org/zh-CN/docs/Web/API/RequestInit
+//
+// The zero value of a field keeps the default, or the value of the Request
+// passed to Fetch or NewRequest.
 type Options struct {
 	Method string
+	// added with canonical keys
 	Header http.Header
-	Body   io.Reader
+	// replaces the values of Header for the names it has. The forbidden
+	// request headers of both, like Host, are dropped like in JS.
+	Headers *Headers
+	// a string, []byte, url.Values, *jsblob.Blob, *jsblob.File,
+	// *multipart.FormData, io.Reader or *jsstreams.ReadableStream[[]byte].
+	// Content-Type is set for the body unless a header has it, a stream is
+	// sent as it is read and needs Duplex "half" like in JS.
+	Body any
+	// "follow" (default), "error" or "manual", a manual redirect returns the
+	// 3xx response instead of an opaque one
+	Redirect string
+	// aborts the request, Fetch returns the reason
+	Signal *jspromise.AbortSignal
+	// "same-origin" (default), "include" or "omit", which requests use the
+	// cookie jar of the client. The origin is the one of Referrer, or of the
+	// URL when there is no referrer.
+	Credentials string
+	// "default", "no-store", "reload", "no-cache", "force-cache" or
+	// "only-if-cached". There is no HTTP cache, the modes set the request
+	// headers the spec asks for and "only-if-cached" always fails.
+	Cache string
+	// the URL of the page that makes the request, "" and "about:client" send
+	// no Referer since there is no document
+	Referrer string
+	// "strict-origin-when-cross-origin" by default
+	ReferrerPolicy string
+	// hashes like "sha384-..." the response body must match, the body is read
+	// before Fetch returns
+	Integrity string
+	// only limits the body to 64 KiB like browsers, a Go request isn't tied
+	// to a page it could outlive
+	Keepalive bool
+	// "half", the only mode, the request body is sent before the response
+	// is read
+	Duplex string
 }
 
 var DefaultOptions = Options{
 	Method: http.MethodGet,
 }
 
-func Fetch(url string, opts ...Options) (*Response, error) {
-	opt := DefaultOptions
-	if len(opts) > 0 {
-		opt = opts[0]
-	}
-
-	req, err := http.NewRequest(opt.Method, url, opt.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	for k, v := range opt.Header {
-		req.Header[k] = v
-	}
-
-	res, err := http.DefaultClient.Do(req)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &Response{
-		Response: res,
-	}, nil
+// https://developer.mozilla.org/zh-CN/docs/Web/API/fetch
+//
+// input is a URL string, a *url.URL or a *Request, see NewRequest. It sends
+// the request with DefaultClient.
+func Fetch(input any, opts ...Options) (*Response, error) {
+	return DefaultClient.Fetch(input, opts...)
 }
 
 type H map[string]any
@@ -1,57 +1,370 @@
 package multipart
 
 import (
-	"bytes"
 	"fmt"
 	"io"
+	"mime"
 	mp "mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"d1y.io/jslike/jsblob"
 )
 
-func NewFormData() *FormData {
-	body := &bytes.Buffer{}
-	w := mp.NewWriter(body)
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData
+//
+// The body is encoded while it is read, a file is only read then. A reader
+// value can be read once, so a FormData with one is sent once. The zero value
+// is an empty FormData, its boundary is made when it is first needed.
+type FormData struct {
+	entries  []FormDataEntry
+	boundary string
+}
 
-	return &FormData{w, body}
+// FormDataEntry is a string entry, or a file entry with a file name and a
+// content type
+type FormDataEntry struct {
+	Name string
+	// a string, a *jsblob.File, a *multipart.FileHeader or an io.Reader
+	Value       any
+	Filename    string
+	ContentType string
 }
 
-type FormData struct {
-	*mp.Writer
-	body *bytes.Buffer
+// IsFile reports whether the entry is a file entry
+func (e FormDataEntry) IsFile() bool {
+	_, ok := e.Value.(string)
+	return !ok
 }
 
-func (fd *FormData) Body() *bytes.Buffer {
-	fd.Close()
-	return fd.body
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/FormData
+func NewFormData() *FormData {
+	return &FormData{}
 }
 
-func (fd *FormData) Append(name string, value any, filename ...string) error {
-	switch v := value.(type) {
-	case *mp.FileHeader:
-		return fd.appendFileHeader(name, v)
-	case string:
-		return fd.WriteField(name, v)
-	case []byte:
-		w, err := fd.CreateFormField(name)
+// ParseFormData reads a multipart/form-data body, a large file is kept in a
+// temporary file like jsblob.FromReader does
+func ParseFormData(r io.Reader, boundary string) (*FormData, error) {
+	reader := mp.NewReader(r, boundary)
+	fd := NewFormData()
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return fd, nil
+		}
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if part.FileName() == "" {
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+			if err := fd.Append(part.FormName(), string(data)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		blob, err := jsblob.FromReader(part)
+		if err != nil {
+			return nil, err
+		}
+		file, err := jsblob.NewFile([]any{blob}, part.FileName(), jsblob.FileOptions{Type: part.Header.Get("Content-Type")})
+		if err != nil {
+			return nil, err
+		}
+		if err := fd.Append(part.FormName(), file); err != nil {
+			return nil, err
 		}
-		_, err = w.Write(v)
-		return err
-	default:
-		return fmt.Errorf("cannot append value type %t", v)
 	}
 }
 
-func (fd *FormData) appendFileHeader(name string, value *mp.FileHeader) error {
-	file, err := value.Open()
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/append
+//
+// value is a string, []byte, *jsblob.Blob, *jsblob.File, *os.File,
+// *multipart.FileHeader or io.Reader, anything but a string or []byte is a
+// file entry. file is the file name and content type, the name is "blob"
+// for a Blob or a reader and the type follows the extension of the name by
+// default.
+func (fd *FormData) Append(name string, value any, file ...string) error {
+	entry, err := newEntry(name, value, file...)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	fw, err := fd.CreateFormFile(name, value.Filename)
+	fd.entries = append(fd.entries, entry)
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/set
+func (fd *FormData) Set(name string, value any, file ...string) error {
+	entry, err := newEntry(name, value, file...)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(fw, file)
+	entries := fd.entries[:0]
+	found := false
+	for _, e := range fd.entries {
+		if e.Name != name {
+			entries = append(entries, e)
+		} else if !found {
+			// the first one keeps its place
+			found = true
+			entries = append(entries, entry)
+		}
+	}
+	if !found {
+		entries = append(entries, entry)
+	}
+	fd.entries = entries
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/delete
+func (fd *FormData) Delete(name string) {
+	entries := fd.entries[:0]
+	for _, e := range fd.entries {
+		if e.Name != name {
+			entries = append(entries, e)
+		}
+	}
+	fd.entries = entries
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/get
+func (fd *FormData) Get(name string) (FormDataEntry, bool) {
+	for _, e := range fd.entries {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return FormDataEntry{}, false
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/getAll
+func (fd *FormData) GetAll(name string) []FormDataEntry {
+	entries := []FormDataEntry{}
+	for _, e := range fd.entries {
+		if e.Name == name {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/has
+func (fd *FormData) Has(name string) bool {
+	_, ok := fd.Get(name)
+	return ok
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/entries
+//
+// In the order they were added
+func (fd *FormData) Entries() []FormDataEntry {
+	return append([]FormDataEntry(nil), fd.entries...)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/FormData/keys
+func (fd *FormData) Keys() []string {
+	keys := make([]string, len(fd.entries))
+	for idx, e := range fd.entries {
+		keys[idx] = e.Name
+	}
+	return keys
+}
+
+func (fd *FormData) Boundary() string {
+	if fd.boundary == "" {
+		fd.boundary = mp.NewWriter(io.Discard).Boundary()
+	}
+	return fd.boundary
+}
+
+// FormDataContentType is the Content-Type of the body with its boundary
+func (fd *FormData) FormDataContentType() string {
+	return "multipart/form-data; boundary=" + fd.Boundary()
+}
+
+// Body encodes the entries as they are read. Close closes the files it
+// opened, a reader value is left to its owner.
+func (fd *FormData) Body() io.ReadCloser {
+	body := &formBody{}
+	readers := make([]io.Reader, 0, len(fd.entries)*3+1)
+	for _, e := range fd.entries {
+		r := content(e)
+		if lazy, ok := r.(*lazyReader); ok {
+			body.files = append(body.files, lazy)
+		}
+		readers = append(readers, strings.NewReader(fd.partHeader(e)), r, strings.NewReader("\r\n"))
+	}
+	readers = append(readers, strings.NewReader("--"+fd.Boundary()+"--\r\n"))
+	body.Reader = io.MultiReader(readers...)
+	return body
+}
+
+// Size is the length of the body, -1 when a reader value has no known length
+func (fd *FormData) Size() int64 {
+	size := int64(len("--" + fd.Boundary() + "--\r\n"))
+	for _, e := range fd.entries {
+		var n int64
+		switch v := e.Value.(type) {
+		case string:
+			n = int64(len(v))
+		case *jsblob.File:
+			n = v.Size()
+		case *mp.FileHeader:
+			n = v.Size
+		case *os.File:
+			info, err := v.Stat()
+			offset, seekErr := v.Seek(0, io.SeekCurrent)
+			if err != nil || seekErr != nil || !info.Mode().IsRegular() {
+				return -1
+			}
+			n = info.Size() - offset
+		case interface{ Len() int }:
+			n = int64(v.Len())
+		default:
+			return -1
+		}
+		size += int64(len(fd.partHeader(e))) + n + 2
+	}
+	return size
+}
+
+// https://html.spec.whatwg.org/multipage/form-control-infrastructure.html#multipart-form-data
+func (fd *FormData) partHeader(e FormDataEntry) string {
+	var b strings.Builder
+	b.WriteString("--" + fd.Boundary() + "\r\n")
+	b.WriteString(`Content-Disposition: form-data; name="` + escapeName(e.Name) + `"`)
+	if e.IsFile() {
+		b.WriteString(`; filename="` + escapeName(e.Filename) + `"`)
+		b.WriteString("\r\nContent-Type: " + e.ContentType)
+	}
+	b.WriteString("\r\n\r\n")
+	return b.String()
+}
+
+var nameEscaper = strings.NewReplacer("\"", "%22", "\r", "%0D", "\n", "%0A")
+
+func escapeName(name string) string {
+	return nameEscaper.Replace(name)
+}
+
+func newEntry(name string, value any, file ...string) (FormDataEntry, error) {
+	entry := FormDataEntry{Name: name, Value: value}
+	switch v := value.(type) {
+	case string:
+		return entry, nil
+	case []byte:
+		entry.Value = string(v)
+		return entry, nil
+	case *jsblob.File:
+		entry.Filename, entry.ContentType = v.Name(), v.Type()
+	case *jsblob.Blob:
+		entry.Filename, entry.ContentType = "blob", v.Type()
+	case *mp.FileHeader:
+		entry.Filename, entry.ContentType = v.Filename, v.Header.Get("Content-Type")
+	case *os.File:
+		entry.Filename = filepath.Base(v.Name())
+	case io.Reader:
+		entry.Filename = "blob"
+	default:
+		return entry, fmt.Errorf("multipart: cannot append value type %T", v)
+	}
+	if len(file) > 0 && file[0] != "" {
+		entry.Filename = file[0]
+	}
+	if len(file) > 1 && file[1] != "" {
+		entry.ContentType = file[1]
+	}
+	if entry.ContentType == "" {
+		entry.ContentType = mime.TypeByExtension(filepath.Ext(entry.Filename))
+	}
+	if entry.ContentType == "" {
+		entry.ContentType = "application/octet-stream"
+	}
+	// the value of a file entry is a File like in JS unless it is read once
+	switch v := value.(type) {
+	case *jsblob.Blob:
+		entry.Value, _ = jsblob.NewFile([]any{v}, entry.Filename, jsblob.FileOptions{Type: entry.ContentType})
+	case *jsblob.File:
+		if v.Name() != entry.Filename || v.Type() != entry.ContentType {
+			entry.Value, _ = jsblob.NewFile([]any{v}, entry.Filename, jsblob.FileOptions{Type: entry.ContentType, LastModified: v.LastModified()})
+		}
+	}
+	return entry, nil
+}
+
+// content reads the value of an entry, a file is opened on the first read
+func content(e FormDataEntry) io.Reader {
+	switch v := e.Value.(type) {
+	case string:
+		return strings.NewReader(v)
+	case *jsblob.File:
+		return &lazyReader{open: func() (io.ReadCloser, error) {
+			return v.Reader(), nil
+		}}
+	case *mp.FileHeader:
+		return &lazyReader{open: func() (io.ReadCloser, error) {
+			return v.Open()
+		}}
+	}
+	return e.Value.(io.Reader)
+}
+
+type formBody struct {
+	io.Reader
+	files []*lazyReader
+}
+
+func (b *formBody) Close() error {
+	for _, file := range b.files {
+		file.Close()
+	}
+	return nil
+}
+
+// lazyReader opens a file on the first Read and closes it at the end, on an
+// error or on Close
+type lazyReader struct {
+	open   func() (io.ReadCloser, error)
+	reader io.ReadCloser
+	err    error
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.reader == nil {
+		reader, err := r.open()
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+		r.reader = reader
+	}
+	n, err := r.reader.Read(p)
+	if err != nil {
+		r.err = err
+		r.closeFile()
+	}
+	return n, err
+}
+
+func (r *lazyReader) Close() error {
+	if r.err == nil {
+		r.err = os.ErrClosed
+	}
+	return r.closeFile()
+}
+
+func (r *lazyReader) closeFile() error {
+	if r.reader == nil {
+		return nil
+	}
+	err := r.reader.Close()
+	r.reader = nil
 	return err
 }
@@ -0,0 +1,174 @@
+package multipart
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"d1y.io/jslike/jsblob"
+)
+
+func TestFormData(t *testing.T) {
+	fd := NewFormData()
+	fd.Append("a", "1")
+	fd.Append("b", []byte("2"))
+	fd.Append("a", "3")
+	fd.Set("a", "4")
+	if !reflect.DeepEqual(fd.Keys(), []string{"a", "b"}) {
+		t.Fatal(fd.Keys())
+	}
+	if a, ok := fd.Get("a"); !ok || a.Value != "4" || a.IsFile() {
+		t.Fatal(a)
+	}
+	fd.Append("b", "5")
+	if all := fd.GetAll("b"); len(all) != 2 || all[0].Value != "2" || all[1].Value != "5" {
+		t.Fatal(all)
+	}
+	fd.Delete("b")
+	if fd.Has("b") || len(fd.Entries()) != 1 {
+		t.Fatal(fd.Entries())
+	}
+	if err := fd.Append("c", 1); err == nil {
+		t.Fatal("expect an error for an int value")
+	}
+}
+
+func TestFormDataFiles(t *testing.T) {
+	blob, _ := jsblob.NewBlob([]any{"blob"}, jsblob.BlobOptions{Type: "text/plain"})
+	path := filepath.Join(t.TempDir(), "data.json")
+	os.WriteFile(path, []byte(`{"a":1}`), 0o644)
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	cases := []struct {
+		value       any
+		file        []string
+		filename    string
+		contentType string
+	}{
+		{blob, nil, "blob", "text/plain"},
+		{blob, []string{"a.bin"}, "a.bin", "text/plain"},
+		{file, nil, "data.json", "application/json"},
+		{strings.NewReader("reader"), nil, "blob", "application/octet-stream"},
+		{strings.NewReader("reader"), []string{"a\"b.txt", "text/csv"}, "a\"b.txt", "text/csv"},
+	}
+	for _, c := range cases {
+		fd := NewFormData()
+		fd.Append("file", c.value, c.file...)
+		entry, _ := fd.Get("file")
+		if !entry.IsFile() || entry.Filename != c.filename || entry.ContentType != c.contentType {
+			t.Fatal(entry)
+		}
+	}
+
+	fd := NewFormData()
+	fd.Append("name\r\n", "go")
+	fd.Append("blob", blob, `a"b.txt`)
+	fd.Append("file", file)
+	// the size of a file is what is left to read
+	size := fd.Size()
+	body, _ := io.ReadAll(fd.Body())
+	if int64(len(body)) != size {
+		t.Fatal(len(body), size)
+	}
+	for _, part := range []string{
+		"Content-Disposition: form-data; name=\"name%0D%0A\"\r\n\r\ngo\r\n",
+		"Content-Disposition: form-data; name=\"blob\"; filename=\"a%22b.txt\"\r\nContent-Type: text/plain\r\n\r\nblob\r\n",
+		"Content-Disposition: form-data; name=\"file\"; filename=\"data.json\"\r\nContent-Type: application/json\r\n\r\n{\"a\":1}\r\n",
+	} {
+		if !strings.Contains(string(body), part) {
+			t.Fatalf("expect %q in %q", part, body)
+		}
+	}
+	if !strings.HasSuffix(string(body), "--"+fd.Boundary()+"--\r\n") {
+		t.Fatalf("%q", body)
+	}
+
+	parsed, err := ParseFormData(strings.NewReader(string(body)), fd.Boundary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := parsed.Get("file")
+	if content, _ := entry.Value.(*jsblob.File).Text(); content != `{"a":1}` || entry.Filename != "data.json" {
+		t.Fatal(entry, content)
+	}
+
+	fd = NewFormData()
+	fd.Append("file", io.MultiReader(strings.NewReader("unknown")))
+	if fd.Size() != -1 {
+		t.Fatal(fd.Size())
+	}
+}
+
+func TestFormDataZeroValue(t *testing.T) {
+	var fd FormData
+	fd.Append("a", "1")
+	body, _ := io.ReadAll(fd.Body())
+	if fd.Boundary() == "" || !strings.HasSuffix(fd.FormDataContentType(), fd.Boundary()) {
+		t.Fatal(fd.FormDataContentType())
+	}
+	parsed, err := ParseFormData(strings.NewReader(string(body)), fd.Boundary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := parsed.Get("a"); a.Value != "1" {
+		t.Fatal(a)
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed int
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed++
+	return nil
+}
+
+func TestLazyReaderClose(t *testing.T) {
+	cases := []struct {
+		name string
+		read func(r *lazyReader)
+	}{
+		{"end", func(r *lazyReader) { io.ReadAll(r) }},
+		{"abort", func(r *lazyReader) { r.Read(make([]byte, 1)); r.Close() }},
+		{"error", func(r *lazyReader) { io.ReadAll(r) }},
+	}
+	for _, c := range cases {
+		var reader io.Reader = strings.NewReader("file")
+		if c.name == "error" {
+			reader = io.MultiReader(strings.NewReader("f"), iotest.ErrReader(errors.New("broken")))
+		}
+		file := &closeRecorder{Reader: reader}
+		r := &lazyReader{open: func() (io.ReadCloser, error) { return file, nil }}
+		c.read(r)
+		r.Close()
+		if file.closed != 1 {
+			t.Fatal(c.name, file.closed)
+		}
+		if _, err := r.Read(make([]byte, 1)); err == nil {
+			t.Fatal(c.name, "expect an error after Close")
+		}
+	}
+
+	// closing the body before a file is read never opens it
+	opened := false
+	r := &lazyReader{open: func() (io.ReadCloser, error) {
+		opened = true
+		return nil, errors.New("opened")
+	}}
+	body := &formBody{Reader: r, files: []*lazyReader{r}}
+	body.Close()
+	if _, err := body.Read(make([]byte, 1)); err == nil || opened {
+		t.Fatal(err, opened)
+	}
+}
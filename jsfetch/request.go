@@ -0,0 +1,507 @@
+package jsfetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"d1y.io/jslike/jspromise"
+	"d1y.io/jslike/jsstreams"
+)
+
+// ErrRedirect is the error of Fetch when a Request with Redirect "error" is
+// redirected
+var ErrRedirect = errors.New("jsfetch: unexpected redirect")
+
+// keepaliveLimit is the body limit of keepalive requests in browsers
+const keepaliveLimit = 64 * 1024
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Request
+//
+// NewRequest fills every field, a Request is sent once, Clone it to send the
+// same request again.
+type Request struct {
+	Method string
+	URL    string
+	// GuardRequest drops the forbidden request headers like Host and
+	// Content-Length, which the client sets
+	Headers *Headers
+	Body    io.Reader
+	// "follow", "error" or "manual"
+	Redirect string
+	Signal   *jspromise.AbortSignal
+	// "same-origin", "include" or "omit"
+	Credentials string
+	// "default", "no-store", "reload", "no-cache", "force-cache" or
+	// "only-if-cached"
+	Cache string
+	// a URL, "about:client" or "" for no referrer
+	Referrer       string
+	ReferrerPolicy string
+	Integrity      string
+	Keepalive      bool
+	// "half"
+	Duplex string
+
+	bodyUsed bool
+	// the length of Body when it is known, -1 when it is not
+	contentLength int64
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Request/Request
+//
+// input is a URL string, a *url.URL or a *Request whose fields opts
+// override, its body moves to the new Request.
+func NewRequest(input any, opts ...Options) (*Request, error) {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	r := &Request{
+		Method:      http.MethodGet,
+		Redirect:    "follow",
+		Credentials: "same-origin",
+		Cache:       "default",
+		Referrer:    "about:client",
+		Duplex:      "half",
+	}
+	switch v := input.(type) {
+	case string:
+		u, err := parseRequestURL(v)
+		if err != nil {
+			return nil, err
+		}
+		r.URL = u
+	case *url.URL:
+		u, err := parseRequestURL(v.String())
+		if err != nil {
+			return nil, err
+		}
+		r.URL = u
+	case *Request:
+		if v.bodyUsed {
+			return nil, fmt.Errorf("jsfetch: request body is already used")
+		}
+		*r = *v
+		if opt.Body == nil && v.Body != nil {
+			v.bodyUsed = true
+		}
+	default:
+		return nil, fmt.Errorf("jsfetch: cannot fetch input type %T", v)
+	}
+
+	var err error
+	if opt.Method != "" {
+		if r.Method, err = normalizeMethod(opt.Method); err != nil {
+			return nil, err
+		}
+	}
+	headers, err := NewHeadersWithGuard(GuardRequest, r.Headers)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Header != nil || opt.Headers != nil {
+		if headers, err = NewHeadersWithGuard(GuardRequest, opt.Header); err != nil {
+			return nil, err
+		}
+		if opt.Headers != nil {
+			for _, name := range opt.Headers.Keys() {
+				headers.Delete(name)
+			}
+			for _, entry := range opt.Headers.list {
+				headers.Append(entry.name, entry.value)
+			}
+		}
+	}
+	r.Headers = headers
+	if opt.Body != nil {
+		if _, ok := opt.Body.(*jsstreams.ReadableStream[[]byte]); ok && opt.Duplex == "" {
+			return nil, fmt.Errorf("jsfetch: duplex \"half\" is required for a ReadableStream body")
+		}
+		extracted, err := extractBody(opt.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body, r.contentLength = extracted.reader, extracted.length
+		if extracted.contentType != "" && !r.Headers.Has("Content-Type") {
+			r.Headers.Set("Content-Type", extracted.contentType)
+		}
+	}
+	if opt.Signal != nil {
+		r.Signal = opt.Signal
+	}
+	if opt.Integrity != "" {
+		r.Integrity = opt.Integrity
+	}
+	if opt.Keepalive {
+		r.Keepalive = true
+	}
+	if r.Redirect, err = oneOf("redirect", opt.Redirect, r.Redirect, "follow", "error", "manual"); err != nil {
+		return nil, err
+	}
+	if r.Credentials, err = oneOf("credentials", opt.Credentials, r.Credentials, "same-origin", "include", "omit"); err != nil {
+		return nil, err
+	}
+	if r.Cache, err = oneOf("cache", opt.Cache, r.Cache, "default", "no-store", "reload", "no-cache", "force-cache", "only-if-cached"); err != nil {
+		return nil, err
+	}
+	if r.ReferrerPolicy, err = oneOf("referrerPolicy", opt.ReferrerPolicy, r.ReferrerPolicy, "no-referrer", "no-referrer-when-downgrade", "same-origin", "origin", "strict-origin", "origin-when-cross-origin", "strict-origin-when-cross-origin", "unsafe-url"); err != nil {
+		return nil, err
+	}
+	if r.Duplex, err = oneOf("duplex", opt.Duplex, r.Duplex, "half"); err != nil {
+		return nil, err
+	}
+	if opt.Referrer != "" && opt.Referrer != "about:client" {
+		u, err := url.Parse(opt.Referrer)
+		if err != nil || !u.IsAbs() {
+			return nil, fmt.Errorf("jsfetch: invalid referrer %q", opt.Referrer)
+		}
+		r.Referrer = u.String()
+	}
+
+	if r.Body != nil && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		return nil, fmt.Errorf("jsfetch: request with %s method cannot have body", r.Method)
+	}
+	if r.Keepalive && r.Body != nil {
+		if r.contentLength < 0 || r.contentLength > keepaliveLimit {
+			return nil, fmt.Errorf("jsfetch: keepalive request body must be a buffer of at most %d bytes", keepaliveLimit)
+		}
+	}
+	return r, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Request/bodyUsed
+func (r *Request) BodyUsed() bool {
+	return r.bodyUsed
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Request/clone
+//
+// The body is teed, what one of the Requests reads first is kept in memory
+// until the other reads it.
+func (r *Request) Clone() (*Request, error) {
+	if r.bodyUsed {
+		return nil, fmt.Errorf("jsfetch: request body is already used")
+	}
+	clone := *r
+	headers, err := NewHeadersWithGuard(GuardRequest, r.Headers)
+	if err != nil {
+		return nil, err
+	}
+	clone.Headers = headers
+	if r.Body != nil {
+		left, right, err := jsstreams.FromReader(r.Body).Tee()
+		if err != nil {
+			return nil, err
+		}
+		r.Body, clone.Body = jsstreams.ToReader(left), jsstreams.ToReader(right)
+	}
+	return &clone, nil
+}
+
+// do sends r with client following the fields of r
+func (r *Request) do(client *http.Client) (*Response, error) {
+	if r.bodyUsed {
+		return nil, fmt.Errorf("jsfetch: request body is already used")
+	}
+	ctx := r.context()
+	if r.Signal != nil {
+		if err := r.Signal.ThrowIfAborted(); err != nil {
+			return nil, err
+		}
+	}
+	if r.Cache == "only-if-cached" {
+		// there is no HTTP cache to answer from
+		return nil, fmt.Errorf("jsfetch: no cached response for %s", r.URL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if r.Body != nil {
+		r.bodyUsed = true
+		if req.ContentLength == 0 && r.contentLength > 0 {
+			// a FormData or a Blob is sent with its length
+			req.ContentLength = r.contentLength
+		}
+	}
+	req.Header = r.Headers.Header()
+	// https://fetch.spec.whatwg.org/#http-network-or-cache-fetch
+	switch r.Cache {
+	case "no-store", "reload":
+		setDefaultHeader(req.Header, "Pragma", "no-cache")
+		setDefaultHeader(req.Header, "Cache-Control", "no-cache")
+	case "no-cache":
+		setDefaultHeader(req.Header, "Cache-Control", "max-age=0")
+	}
+	r.setReferer(req)
+
+	c := *client
+	if client.Jar != nil {
+		c.Jar = &credentialsJar{jar: client.Jar, credentials: r.Credentials, origin: r.origin()}
+	}
+	redirected := false
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		switch r.Redirect {
+		case "error":
+			return ErrRedirect
+		case "manual":
+			return http.ErrUseLastResponse
+		}
+		if len(via) >= 20 {
+			return fmt.Errorf("jsfetch: too many redirects")
+		}
+		r.setReferer(req)
+		redirected = true
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		}
+		return nil
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		if r.Signal != nil && r.Signal.Aborted() {
+			return nil, r.Signal.Reason()
+		}
+		return nil, err
+	}
+	response := &Response{Response: res, redirected: redirected}
+	if r.Redirect == "manual" && res.StatusCode >= 300 && res.StatusCode < 400 && res.Header.Get("Location") != "" {
+		response.responseType = "opaqueredirect"
+	}
+	if r.Integrity != "" {
+		body, err := response.BodyAsBytes()
+		if err != nil {
+			return nil, err
+		}
+		if !matchIntegrity(r.Integrity, body) {
+			return nil, fmt.Errorf("jsfetch: integrity check failed for %s", r.URL)
+		}
+	}
+	return response, nil
+}
+
+func (r *Request) context() context.Context {
+	if r.Signal != nil {
+		return r.Signal.Context()
+	}
+	return context.Background()
+}
+
+// origin is the origin same-origin credentials are checked against, the
+// referrer when there is one like the document of a browser, or the URL
+func (r *Request) origin() string {
+	if referrer, err := url.Parse(r.Referrer); err == nil && referrer.IsAbs() && referrer.Host != "" {
+		return origin(referrer)
+	}
+	u, _ := url.Parse(r.URL)
+	return origin(u)
+}
+
+// setReferer sets the Referer header of req from the referrer and the policy
+// https://w3c.github.io/webappsec-referrer-policy/#determine-requests-referrer
+func (r *Request) setReferer(req *http.Request) {
+	req.Header.Del("Referer")
+	referrer, err := url.Parse(r.Referrer)
+	if err != nil || !referrer.IsAbs() || referrer.Host == "" {
+		// "about:client" has no document to refer to outside of a browser
+		return
+	}
+	referrer.User = nil
+	referrer.Fragment = ""
+	referrer.RawFragment = ""
+	full := referrer.String()
+	originOnly := origin(referrer) + "/"
+	if len(full) > 4096 {
+		full = originOnly
+	}
+	sameOrigin := origin(referrer) == origin(req.URL)
+	downgrade := referrer.Scheme == "https" && req.URL.Scheme != "https"
+
+	var value string
+	switch r.ReferrerPolicy {
+	case "no-referrer":
+	case "unsafe-url":
+		value = full
+	case "origin":
+		value = originOnly
+	case "strict-origin":
+		if !downgrade {
+			value = originOnly
+		}
+	case "no-referrer-when-downgrade":
+		if !downgrade {
+			value = full
+		}
+	case "same-origin":
+		if sameOrigin {
+			value = full
+		}
+	case "origin-when-cross-origin":
+		if sameOrigin {
+			value = full
+		} else {
+			value = originOnly
+		}
+	default:
+		// strict-origin-when-cross-origin
+		switch {
+		case sameOrigin:
+			value = full
+		case !downgrade:
+			value = originOnly
+		}
+	}
+	if value != "" {
+		req.Header.Set("Referer", value)
+	}
+}
+
+// credentialsJar limits the cookies of a request to its credentials mode
+type credentialsJar struct {
+	jar         http.CookieJar
+	credentials string
+	origin      string
+}
+
+func (j *credentialsJar) allowed(u *url.URL) bool {
+	switch j.credentials {
+	case "omit":
+		return false
+	case "include":
+		return true
+	}
+	return origin(u) == j.origin
+}
+
+func (j *credentialsJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if j.allowed(u) {
+		j.jar.SetCookies(u, cookies)
+	}
+}
+
+func (j *credentialsJar) Cookies(u *url.URL) []*http.Cookie {
+	if j.allowed(u) {
+		return j.jar.Cookies(u)
+	}
+	return nil
+}
+
+// https://html.spec.whatwg.org/multipage/browsers.html#ascii-serialisation-of-an-origin
+func origin(u *url.URL) string {
+	if u == nil || !u.IsAbs() {
+		return "null"
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	port := u.Port()
+	if scheme == "http" && port == "80" || scheme == "https" && port == "443" {
+		port = ""
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	return scheme + "://" + host
+}
+
+func parseRequestURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() {
+		return "", fmt.Errorf("jsfetch: invalid URL %q", rawURL)
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("jsfetch: request URL %q includes credentials", rawURL)
+	}
+	return u.String(), nil
+}
+
+// https://fetch.spec.whatwg.org/#concept-method-normalize
+func normalizeMethod(method string) (string, error) {
+	if !isToken(method) {
+		return "", fmt.Errorf("jsfetch: invalid method %q", method)
+	}
+	upper := strings.ToUpper(method)
+	switch upper {
+	case "CONNECT", "TRACE", "TRACK":
+		return "", fmt.Errorf("jsfetch: forbidden method %q", method)
+	case http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPost, http.MethodPut:
+		return upper, nil
+	}
+	// "patch" stays lower case like in browsers
+	return method, nil
+}
+
+func oneOf(name, value, fallback string, values ...string) (string, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	for _, v := range values {
+		if v == value {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("jsfetch: invalid %s option %q", name, value)
+}
+
+func setDefaultHeader(header http.Header, name, value string) {
+	if header.Get(name) == "" {
+		header.Set(name, value)
+	}
+}
+
+// https://w3c.github.io/webappsec-subresource-integrity/#does-response-match-metadatalist
+func matchIntegrity(metadata string, body []byte) bool {
+	algorithms := map[string]func() hash.Hash{
+		"sha256": sha256.New,
+		"sha384": sha512.New384,
+		"sha512": sha512.New,
+	}
+	strength := map[string]int{"sha256": 1, "sha384": 2, "sha512": 3}
+	var strongest string
+	var expected []string
+	for _, item := range strings.Fields(metadata) {
+		algorithm, value, ok := strings.Cut(item, "-")
+		if !ok || algorithms[algorithm] == nil {
+			continue
+		}
+		// options after "?" are reserved
+		value, _, _ = strings.Cut(value, "?")
+		switch {
+		case strength[algorithm] > strength[strongest]:
+			strongest = algorithm
+			expected = []string{value}
+		case algorithm == strongest:
+			expected = append(expected, value)
+		}
+	}
+	if strongest == "" {
+		// no metadata the client understands
+		return true
+	}
+	h := algorithms[strongest]()
+	h.Write(body)
+	digest := h.Sum(nil)
+	for _, value := range expected {
+		if value == base64.StdEncoding.EncodeToString(digest) || value == base64.RawURLEncoding.EncodeToString(digest) {
+			return true
+		}
+	}
+	return false
+}
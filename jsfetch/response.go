@@ -1,20 +1,277 @@
 package jsfetch
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"d1y.io/jslike/jsblob"
+	"d1y.io/jslike/jsencoding"
+	"d1y.io/jslike/jsfetch/multipart"
+	"d1y.io/jslike/jsstreams"
 )
 
 type Response struct {
 	*http.Response
-	body []byte
+	body         []byte
+	headers      *Headers
+	redirected   bool
+	bodyUsed     bool
+	responseType string
+	stream       *jsstreams.ReadableStream[[]byte]
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/Response#options
+type ResponseInit struct {
+	// 200 by default
+	Status     int
+	StatusText string
+	Header     http.Header
+	Headers    *Headers
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/Response
+//
+// body is nil, a string, []byte, *jsblob.Blob, url.Values, an io.Reader or a
+// *jsstreams.ReadableStream[[]byte], the Content-Type follows the body like in
+// JS when init has none.
+func NewResponse(body any, init ...ResponseInit) (*Response, error) {
+	var opts ResponseInit
+	if len(init) > 0 {
+		opts = init[0]
+	}
+	if opts.Status == 0 {
+		opts.Status = http.StatusOK
+	}
+	if opts.Status < 200 || opts.Status > 599 {
+		return nil, fmt.Errorf("jsfetch: response status %d is out of range", opts.Status)
+	}
+	if opts.StatusText == "" {
+		opts.StatusText = http.StatusText(opts.Status)
+	}
+
+	headers, err := NewHeaders(opts.Header)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Headers != nil {
+		for _, name := range opts.Headers.Keys() {
+			headers.Delete(name)
+		}
+		for _, entry := range opts.Headers.list {
+			headers.Append(entry.name, entry.value)
+		}
+	}
+
+	extracted, err := extractBody(body)
+	if err != nil {
+		return nil, err
+	}
+	reader := extracted.reader
+	if reader != nil {
+		switch opts.Status {
+		case http.StatusSwitchingProtocols, http.StatusNoContent, http.StatusResetContent, http.StatusNotModified:
+			return nil, fmt.Errorf("jsfetch: response with status %d cannot have body", opts.Status)
+		}
+		if extracted.contentType != "" && !headers.Has("Content-Type") {
+			headers.Set("Content-Type", extracted.contentType)
+		}
+	} else {
+		reader = http.NoBody
+	}
+
+	res := &http.Response{
+		Status:        strconv.Itoa(opts.Status) + " " + opts.StatusText,
+		StatusCode:    opts.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers.Header(),
+		Body:          io.NopCloser(reader),
+		ContentLength: extracted.length,
+	}
+	return &Response{Response: res, responseType: "default", stream: extracted.stream}, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/json_static
+func ResponseJSON(v any, init ...ResponseInit) (*Response, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var opts ResponseInit
+	if len(init) > 0 {
+		opts = init[0]
+	}
+	header := opts.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if header.Get("Content-Type") == "" && (opts.Headers == nil || !opts.Headers.Has("Content-Type")) {
+		header.Set("Content-Type", "application/json")
+	}
+	opts.Header = header
+	return NewResponse(data, opts)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/error_static
+//
+// A network error, its status is 0 and its type "error"
+func ResponseError() *Response {
+	res := &http.Response{
+		Status:     "0 ",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+	}
+	return &Response{Response: res, responseType: "error"}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/redirect_static
+//
+// status is 302 by default
+func ResponseRedirect(rawURL string, status ...int) (*Response, error) {
+	code := http.StatusFound
+	if len(status) > 0 {
+		code = status[0]
+	}
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, fmt.Errorf("jsfetch: invalid redirect status %d", code)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewResponse(nil, ResponseInit{Status: code, Header: http.Header{"Location": {u.String()}}})
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/ok
+func (res *Response) Ok() bool {
+	return res.StatusCode >= 200 && res.StatusCode <= 299
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/statusText
+func (res *Response) StatusText() string {
+	return strings.TrimPrefix(res.Status, strconv.Itoa(res.StatusCode)+" ")
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/redirected
+func (res *Response) Redirected() bool {
+	return res.redirected
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/url
+//
+// The URL after redirects without the fragment, "" for a Response made by
+// NewResponse
+func (res *Response) URL() string {
+	if res.Request == nil || res.Request.URL == nil {
+		return ""
+	}
+	u := *res.Request.URL
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/type
+//
+// "basic" for a fetched Response, "opaqueredirect" for one that stopped at a
+// redirect with Redirect "manual", "default" or "error" for the constructors
+func (res *Response) Type() string {
+	if res.responseType == "" {
+		return "basic"
+	}
+	return res.responseType
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/bodyUsed
+func (res *Response) BodyUsed() bool {
+	return res.bodyUsed || res.stream != nil && res.stream.Disturbed()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/body
+//
+// The stream reads the body a chunk at a time as it is read, so a body larger
+// than memory can be piped somewhere. The method hides the Body field of
+// http.Response, which stays reachable as res.Response.Body. Once the stream
+// is made the body is only read through it.
+func (res *Response) Body() *jsstreams.ReadableStream[[]byte] {
+	if res.body != nil && (res.stream == nil || res.stream.Disturbed()) {
+		// read by the other body methods, which keep the body
+		res.stream = jsstreams.FromReader(bytes.NewReader(res.body))
+	}
+	if res.stream == nil {
+		switch {
+		case res.Response.Body != nil:
+			res.stream = jsstreams.FromReader(res.Response.Body)
+		default:
+			res.stream = jsstreams.FromReader(http.NoBody)
+		}
+	}
+	return res.stream
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/headers
+//
+// The headers are immutable, Set-Cookie is kept like Node.js does
+func (res *Response) Headers() *Headers {
+	if res.headers == nil {
+		// http.Header values can't hold an invalid value, skip invalid names
+		headers := &Headers{}
+		for name, values := range res.Header {
+			for _, value := range values {
+				_ = headers.Append(name, value)
+			}
+		}
+		headers.guard = GuardImmutable
+		res.headers = headers
+	}
+	return res.headers
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/clone
+//
+// The body is teed, what one of the Responses reads first is kept in memory
+// until the other reads it.
+func (res *Response) Clone() (*Response, error) {
+	if res.BodyUsed() && res.body == nil {
+		return nil, fmt.Errorf("jsfetch: response body is already used")
+	}
+	clone := *res
+	httpResponse := *res.Response
+	httpResponse.Header = res.Header.Clone()
+	clone.Response = &httpResponse
+	if res.body == nil && (res.stream != nil || res.Response.Body != nil) {
+		left, right, err := res.Body().Tee()
+		if err != nil {
+			return nil, err
+		}
+		// the Body fields read the branches for code that reads them directly
+		res.stream, clone.stream = left, right
+		res.Response.Body, httpResponse.Body = jsstreams.ToReader(left), jsstreams.ToReader(right)
+	}
+	return &clone, nil
 }
 
 func (res *Response) BodyAsBytes() ([]byte, error) {
 	if res.body == nil {
-		defer res.Body.Close()
-		bytes, err := io.ReadAll(res.Body)
+		body, err := res.takeBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		bytes, err := io.ReadAll(body)
 		if err != nil {
 			return nil, err
 		}
@@ -25,6 +282,96 @@ func (res *Response) BodyAsBytes() ([]byte, error) {
 	return res.body, nil
 }
 
+// takeBody is the body to read once, through the stream when there is one
+func (res *Response) takeBody() (io.ReadCloser, error) {
+	if res.bodyUsed {
+		return nil, fmt.Errorf("jsfetch: response body is already used")
+	}
+	body := res.Response.Body
+	if res.stream != nil {
+		if res.stream.Locked() || res.stream.Disturbed() {
+			return nil, fmt.Errorf("jsfetch: response body is already used")
+		}
+		body = jsstreams.ToReader(res.stream)
+	}
+	if body == nil {
+		body = http.NoBody
+	}
+	res.bodyUsed = true
+	return body, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/arrayBuffer
+func (res *Response) ArrayBuffer() ([]byte, error) {
+	return res.BodyAsBytes()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/blob
+//
+// The body is not kept by the Response, a large body is stored in a
+// temporary file like jsblob.FromReader does, which Blob.Close frees.
+func (res *Response) Blob() (*jsblob.Blob, error) {
+	options := jsblob.BlobOptions{Type: res.Header.Get("Content-Type")}
+	if res.body != nil {
+		return jsblob.NewBlob([]any{res.body}, options)
+	}
+	body, err := res.takeBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return jsblob.FromReader(body, options)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/formData
+//
+// The body is multipart/form-data or application/x-www-form-urlencoded, a
+// multipart body is parsed as it is read and a large file is kept in a
+// temporary file like jsblob.FromReader does, which Blob.Close frees.
+func (res *Response) FormData() (*multipart.FormData, error) {
+	mediaType, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("jsfetch: invalid form content type: %w", err)
+	}
+	switch mediaType {
+	case "multipart/form-data":
+		if params["boundary"] == "" {
+			return nil, fmt.Errorf("jsfetch: multipart body without a boundary")
+		}
+		if res.body != nil {
+			return multipart.ParseFormData(bytes.NewReader(res.body), params["boundary"])
+		}
+		body, err := res.takeBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return multipart.ParseFormData(body, params["boundary"])
+	case "application/x-www-form-urlencoded":
+		body, err := res.BodyAsBytes()
+		if err != nil {
+			return nil, err
+		}
+		// url.ParseQuery loses the order of the entries
+		form := multipart.NewFormData()
+		for _, pair := range strings.Split(string(body), "&") {
+			if pair == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(pair, "=")
+			if name, err = url.QueryUnescape(name); err != nil {
+				return nil, err
+			}
+			if value, err = url.QueryUnescape(value); err != nil {
+				return nil, err
+			}
+			form.Append(name, value)
+		}
+		return form, nil
+	}
+	return nil, fmt.Errorf("jsfetch: cannot parse %q as form data", mediaType)
+}
+
 func (res *Response) BindJSON(v any) error {
 	body, err := res.BodyAsBytes()
 	if err != nil {
@@ -40,10 +387,15 @@ func (res *Response) JSON() (map[string]any, error) {
 	return result, err
 }
 
+// https://developer.mozilla.org/zh-CN/docs/Web/API/Response/text
+//
+// The body is decoded with the charset of Content-Type, UTF-8 when there is
+// none or it is unknown, a byte order mark wins over both like in browsers.
 func (res *Response) Text() (string, error) {
 	body, err := res.BodyAsBytes()
 	if err != nil {
 		return "", err
 	}
-	return string(body), nil
+	_, params, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	return jsencoding.Decode(body, params["charset"]), nil
 }
@@ -0,0 +1,204 @@
+package jsfetch
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"d1y.io/jslike/jsblob"
+	"d1y.io/jslike/jsfetch/multipart"
+	"d1y.io/jslike/jsstreams"
+)
+
+func TestNewResponse(t *testing.T) {
+	res, err := NewResponse("hello", ResponseInit{Status: http.StatusCreated, StatusText: "Made"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Ok() || res.StatusText() != "Made" || res.Type() != "default" || res.URL() != "" || res.Headers().Get("content-type") != "text/plain;charset=UTF-8" {
+		t.Fatal(res.Status, res.Type(), res.Header)
+	}
+	clone, err := res.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := res.Text(); text != "hello" || !res.BodyUsed() {
+		t.Fatal(text)
+	}
+	if text, _ := clone.Text(); text != "hello" {
+		t.Fatal(text)
+	}
+
+	res, _ = ResponseJSON(map[string]int{"a": 1})
+	if data, err := res.JSON(); err != nil || data["a"] != 1.0 || res.Header.Get("Content-Type") != "application/json" {
+		t.Fatal(data, err)
+	}
+
+	if res := ResponseError(); res.Type() != "error" || res.StatusCode != 0 || res.Ok() {
+		t.Fatal(res)
+	}
+	res, err = ResponseRedirect("https://example.com/next", http.StatusSeeOther)
+	if err != nil || res.StatusCode != http.StatusSeeOther || res.Header.Get("Location") != "https://example.com/next" {
+		t.Fatal(res, err)
+	}
+	if _, err := ResponseRedirect("https://example.com", http.StatusOK); err == nil {
+		t.Fatal("expect an error for a status that isn't a redirect")
+	}
+	if _, err := NewResponse("body", ResponseInit{Status: http.StatusNoContent}); err == nil {
+		t.Fatal("expect an error for a body with status 204")
+	}
+
+	blob, _ := jsblob.NewBlob([]any{"data"}, jsblob.BlobOptions{Type: "text/csv"})
+	res, _ = NewResponse(blob)
+	if blob, err := res.Blob(); err != nil || blob.Type() != "text/csv" || blob.Size() != 4 {
+		t.Fatal(blob, err)
+	}
+	if _, err := res.Blob(); err == nil || !res.BodyUsed() {
+		t.Fatal("expect the body to be used by Blob")
+	}
+}
+
+func TestResponseTextCharset(t *testing.T) {
+	cases := []struct {
+		contentType string
+		body        string
+		text        string
+	}{
+		{"text/html; charset=GBK", "\xd6\xd0\xce\xc4", "中文"},
+		{"text/plain;charset=gb2312", "\xef\xbb\xbf中文", "中文"},
+		{"text/plain; charset=latin1", "caf\xe9", "café"},
+		{"text/plain; charset=bogus", "中文", "中文"},
+		{"", "中文", "中文"},
+	}
+	for _, c := range cases {
+		res, _ := NewResponse([]byte(c.body), ResponseInit{Header: http.Header{"Content-Type": {c.contentType}}})
+		if text, err := res.Text(); err != nil || text != c.text {
+			t.Fatal(c.contentType, text, err)
+		}
+	}
+}
+
+func TestResponseFormData(t *testing.T) {
+	res, _ := NewResponse("b=2&a=1&a=%E4%BD%A0", ResponseInit{Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}})
+	form, err := res.FormData()
+	if err != nil || !reflect.DeepEqual(form.Keys(), []string{"b", "a", "a"}) || form.GetAll("a")[1].Value != "你" {
+		t.Fatal(form, err)
+	}
+
+	data := multipart.NewFormData()
+	data.Append("name", "你好世界")
+	data.Append("file", strings.NewReader("content"), "a.txt")
+	res, _ = NewResponse(data)
+	form, err = res.FormData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := form.Get("name"); name.Value != "你好世界" {
+		t.Fatal(name)
+	}
+	file, _ := form.Get("file")
+	if blob, ok := file.Value.(*jsblob.File); !ok || blob.Name() != "a.txt" || blob.Type() != "text/plain; charset=utf-8" {
+		t.Fatal(file)
+	}
+	if text, _ := file.Value.(*jsblob.File).Text(); text != "content" {
+		t.Fatal(text)
+	}
+	if _, err := res.FormData(); err == nil || !res.BodyUsed() {
+		t.Fatal("expect the body to be used by FormData")
+	}
+
+	res, _ = NewResponse(strings.NewReader("plain"))
+	if _, err := res.FormData(); err == nil {
+		t.Fatal("expect an error without a form content type")
+	}
+}
+
+func TestResponseClone(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	res, err := Fetch(server.URL+"/echo", Options{Method: "POST", Body: BodyText(strings.Repeat("a", 100000))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Type() != "basic" || res.URL() != server.URL+"/echo" || res.StatusText() != "OK" {
+		t.Fatal(res.Type(), res.URL(), res.StatusText())
+	}
+	clone, _ := res.Clone()
+	first, _ := io.ReadAll(clone.Response.Body)
+	second, _ := res.Text()
+	if len(first) != 100000 || string(first) != second {
+		t.Fatal(len(first), len(second))
+	}
+
+	res, err = Fetch(server.URL+"/redirect", Options{Redirect: "manual"})
+	if err != nil || res.Type() != "opaqueredirect" {
+		t.Fatal(res, err)
+	}
+}
+
+func TestResponseBody(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	data := strings.Repeat("jslike", 100000)
+	upload := jsstreams.FromReader(strings.NewReader(data))
+	if _, err := Fetch(server.URL+"/echo", Options{Method: "POST", Body: upload}); err == nil {
+		t.Fatal("expect a stream body without duplex to fail")
+	}
+	res, err := Fetch(server.URL+"/echo", Options{Method: "POST", Body: upload, Duplex: "half"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Header.Get("X-Transfer-Encoding") != "chunked" {
+		t.Fatal("expect a chunked upload, got", res.Header.Get("X-Transfer-Encoding"))
+	}
+	clone, err := res.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := res.Body().PipeTo(jsstreams.FromWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != data || !res.BodyUsed() {
+		t.Fatal(buf.Len(), res.BodyUsed())
+	}
+	if _, err := res.Text(); err == nil {
+		t.Fatal("expect a used body")
+	}
+	if _, err := res.Clone(); err == nil {
+		t.Fatal("expect a used body")
+	}
+
+	reader, _ := clone.Body().GetReader()
+	chunk, _, err := reader.Read()
+	if err != nil || len(chunk) == 0 || len(chunk) >= len(data) {
+		t.Fatal("expect the body a chunk at a time", len(chunk), err)
+	}
+	reader.Cancel(nil)
+	if !clone.BodyUsed() {
+		t.Fatal("expect a used body")
+	}
+
+	res, _ = NewResponse(jsstreams.FromReader(strings.NewReader("hello")))
+	if text, err := res.Text(); err != nil || text != "hello" {
+		t.Fatal(text, err)
+	}
+	if chunk, _, _ := readFirst(res.Body()); string(chunk) != "hello" {
+		t.Fatal(string(chunk))
+	}
+}
+
+func readFirst(s *jsstreams.ReadableStream[[]byte]) ([]byte, bool, error) {
+	reader, err := s.GetReader()
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.ReleaseLock()
+	return reader.Read()
+}
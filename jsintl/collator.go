@@ -0,0 +1,189 @@
+package jsintl
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Collator/Collator#options
+type CollatorOptions struct {
+	// "sort" (default) or "search"
+	Usage string
+	// "base", "accent", "case" or "variant" (default)
+	Sensitivity string
+	// ignore spaces and punctuation, "a-b" equals "ab"
+	IgnorePunctuation bool
+	// compare digit sequences by value, "file9" sorts before "file10"
+	Numeric bool
+	// "upper", "lower" or "false" (default), which case sorts first
+	CaseFirst string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Collator/resolvedOptions
+type CollatorResolvedOptions struct {
+	Locale            string
+	Usage             string
+	Sensitivity       string
+	IgnorePunctuation bool
+	Collation         string
+	Numeric           bool
+	CaseFirst         string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Collator
+//
+// The comparison is the Unicode Collation Algorithm with the DUCET and CLDR
+// tailorings of golang.org/x/text/collate, so every locale it knows is
+// supported, not only the ones jsintl formats numbers and dates for.
+type Collator struct {
+	options CollatorResolvedOptions
+	// the collate.Collator reuses its buffers, so it is not safe for
+	// concurrent use
+	mutex    sync.Mutex
+	collator *collate.Collator
+	// compares without case and width for caseFirst: "upper"
+	secondary *collate.Collator
+	buffers   [2]collate.Buffer
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Collator/Collator
+func NewCollator(tag string, options ...CollatorOptions) (*Collator, error) {
+	var opts CollatorOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	l := parseLocale(tag)
+	if l.language == "" {
+		l = locale{language: defaultLocale, keywords: l.keywords}
+	}
+	c := &Collator{}
+	resolved := &c.options
+	resolved.Locale = l.String()
+	resolved.Collation = "default"
+	resolved.IgnorePunctuation = opts.IgnorePunctuation
+	// the options win over the "-u-kn-" and "-u-kf-" keywords
+	resolved.Numeric = opts.Numeric || l.keywords["kn"] == "true"
+	var err error
+	if resolved.Usage, err = oneOf("usage", opts.Usage, "sort", "search"); err != nil {
+		return nil, err
+	}
+	if resolved.Sensitivity, err = oneOf("sensitivity", opts.Sensitivity, "variant", "base", "accent", "case"); err != nil {
+		return nil, err
+	}
+	caseFirst := opts.CaseFirst
+	if caseFirst == "" {
+		caseFirst = l.keywords["kf"]
+		if caseFirst != "upper" && caseFirst != "lower" {
+			caseFirst = ""
+		}
+	}
+	if resolved.CaseFirst, err = oneOf("caseFirst", caseFirst, "false", "upper", "lower"); err != nil {
+		return nil, err
+	}
+
+	base, err := language.Parse(resolved.Locale)
+	if err != nil {
+		base = language.English
+	}
+	var collateOptions []collate.Option
+	switch resolved.Sensitivity {
+	case "base":
+		collateOptions = append(collateOptions, collate.Loose)
+	case "accent":
+		collateOptions = append(collateOptions, collate.IgnoreCase, collate.IgnoreWidth)
+	}
+	if resolved.Numeric {
+		collateOptions = append(collateOptions, collate.Numeric)
+	}
+	if resolved.IgnorePunctuation {
+		// alternate=shifted, only honored when comparing sort keys
+		shifted, _ := base.SetTypeForKey("ka", "shifted")
+		collateOptions = append(collateOptions, collate.OptionsFromTag(shifted))
+	}
+	c.collator = collate.New(base, collateOptions...)
+	if resolved.CaseFirst == "upper" && (resolved.Sensitivity == "variant" || resolved.Sensitivity == "case") {
+		c.secondary = collate.New(base, append([]collate.Option{collate.IgnoreCase, collate.IgnoreWidth}, collateOptions...)...)
+	}
+	return c, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Collator/resolvedOptions
+func (c *Collator) ResolvedOptions() CollatorResolvedOptions {
+	return c.options
+}
+
+// Compare returns a negative number when a sorts before b, a positive number
+// when it sorts after and 0 when they are equal for the sensitivity
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Collator/compare
+func (c *Collator) Compare(a, b string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.options.Sensitivity == "case" {
+		// collate.IgnoreDiacritics keeps some accents on the tertiary level
+		a, b = stripMarks(a), stripMarks(b)
+	}
+	if c.secondary != nil {
+		if result := c.compare(c.secondary, a, b); result != 0 {
+			return result
+		}
+		// the tables sort lower case first, swapping the case of both
+		// strings flips only that part of the tertiary order
+		a, b = swapCase(a), swapCase(b)
+	}
+	return c.compare(c.collator, a, b)
+}
+
+func (c *Collator) compare(collator *collate.Collator, a, b string) int {
+	if !c.options.IgnorePunctuation {
+		return collator.CompareString(a, b)
+	}
+	c.buffers[0].Reset()
+	c.buffers[1].Reset()
+	return bytes.Compare(collator.KeyFromString(&c.buffers[0], a), collator.KeyFromString(&c.buffers[1], b))
+}
+
+func stripMarks(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(s))
+}
+
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+// Comparator adapts c to string types such as jsstring.JSString, for
+// slices.SortFunc or JSArray.Sort
+func Comparator[S ~string](c *Collator) func(a, b S) int {
+	return func(a, b S) int {
+		return c.Compare(string(a), string(b))
+	}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/String/localeCompare
+//
+// LocaleCompare is String.prototype.localeCompare for a Go string,
+// jsstring.JSString has it as a method. It builds a Collator on every call,
+// sorting many strings is faster with a Collator and Comparator.
+func LocaleCompare(s, compareString, locale string, options ...CollatorOptions) (int, error) {
+	collator, err := NewCollator(locale, options...)
+	if err != nil {
+		return 0, err
+	}
+	return collator.Compare(s, compareString), nil
+}
@@ -0,0 +1,83 @@
+package jsintl
+
+import (
+	"fmt"
+	"testing"
+
+	"d1y.io/jslike/jsarray"
+)
+
+func TestCollator(t *testing.T) {
+	cases := []struct {
+		locale  string
+		options CollatorOptions
+		a, b    string
+		expect  int
+	}{
+		{"en", CollatorOptions{}, "a", "b", -1},
+		{"en", CollatorOptions{}, "a", "A", -1},
+		{"en", CollatorOptions{}, "résumé", "resume", 1},
+		{"en", CollatorOptions{}, "file10", "file9", -1},
+		{"en", CollatorOptions{Numeric: true}, "file10", "file9", 1},
+		{"en-u-kn-true", CollatorOptions{}, "file10", "file9", 1},
+		{"en", CollatorOptions{Sensitivity: "base"}, "a", "Á", 0},
+		{"en", CollatorOptions{Sensitivity: "accent"}, "a", "A", 0},
+		{"en", CollatorOptions{Sensitivity: "accent"}, "a", "á", -1},
+		{"en", CollatorOptions{Sensitivity: "case"}, "a", "á", 0},
+		{"en", CollatorOptions{Sensitivity: "case"}, "a", "A", -1},
+		{"en", CollatorOptions{IgnorePunctuation: true}, "co-op", "coop", 0},
+		{"en", CollatorOptions{CaseFirst: "upper"}, "a", "A", 1},
+		{"en", CollatorOptions{CaseFirst: "upper"}, "a", "B", -1},
+		{"de", CollatorOptions{}, "ä", "z", -1},
+		{"sv", CollatorOptions{}, "ä", "z", 1},
+	}
+	for _, c := range cases {
+		collator, err := NewCollator(c.locale, c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := collator.Compare(c.a, c.b); sign(result) != c.expect {
+			t.Errorf("%s %+v: compare %q and %q, expect %d, got %d", c.locale, c.options, c.a, c.b, c.expect, result)
+		}
+	}
+	if _, err := NewCollator("en", CollatorOptions{Sensitivity: "strict"}); err == nil {
+		t.Fatal("expect an error for an invalid sensitivity")
+	}
+}
+
+func TestCollatorComparator(t *testing.T) {
+	collator, err := NewCollator("en", CollatorOptions{Numeric: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files jsarray.JSArray[string] = []string{"file10.txt", "File2.txt", "file1.txt", "éclair.txt", "zeta.txt"}
+	files.Sort(Comparator[string](collator))
+	if fmt.Sprint(files) != "[éclair.txt file1.txt File2.txt file10.txt zeta.txt]" {
+		t.Fatal(files)
+	}
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestLocaleCompare(t *testing.T) {
+	if result, err := LocaleCompare("réservé", "RESERVE", "en"); result != 1 || err != nil {
+		t.Fatal(result, err)
+	}
+	if result, _ := LocaleCompare("réservé", "RESERVE", "en", CollatorOptions{Sensitivity: "base"}); result != 0 {
+		t.Fatal(result)
+	}
+	if result, _ := LocaleCompare("a", "b", ""); result != -1 {
+		t.Fatal(result)
+	}
+	if _, err := LocaleCompare("a", "b", "en", CollatorOptions{Sensitivity: "bogus"}); err == nil {
+		t.Fatal("expect an error for invalid options")
+	}
+}
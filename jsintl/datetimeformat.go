@@ -0,0 +1,771 @@
+package jsintl
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type datesData struct {
+	// "h12" or "h23", the 12 hour variant is "h11" when the patterns use K
+	HourCycle string `json:"hourCycle"`
+	// width => names, weekdays start on Sunday like time.Weekday
+	Months     map[string][]string `json:"months"`
+	Weekdays   map[string][]string `json:"weekdays"`
+	DayPeriods []string            `json:"dayPeriods"`
+	Eras       []string            `json:"eras"`
+	GMTFormat  string              `json:"gmtFormat"`
+	// style => pattern
+	DateFormats     map[string]string `json:"dateFormats"`
+	TimeFormats     map[string]string `json:"timeFormats"`
+	DateTimeFormats map[string]string `json:"dateTimeFormats"`
+	// skeleton => pattern
+	AvailableFormats map[string]string `json:"availableFormats"`
+}
+
+// Bool returns a pointer to v, for the optional hour12 option
+func Bool(v bool) *bool {
+	return &v
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat/DateTimeFormat#options
+//
+// The styles can't be mixed with the field options, without either the date
+// is formatted with numeric year, month and day.
+type DateTimeFormatOptions struct {
+	// "full", "long", "medium" or "short"
+	DateStyle string
+	TimeStyle string
+
+	// "long", "short" or "narrow"
+	Weekday string
+	Era     string
+	// "numeric" or "2-digit"
+	Year string
+	// "numeric", "2-digit", "long", "short" or "narrow"
+	Month string
+	// "numeric" or "2-digit"
+	Day    string
+	Hour   string
+	Minute string
+	Second string
+	// 1 to 3, 0 hides the fraction
+	FractionalSecondDigits int
+	// "short", "long", "shortOffset" or "longOffset"
+	TimeZoneName string
+
+	// an IANA name such as "Asia/Shanghai" or an offset such as "+08:00",
+	// defaults to time.Local, named by its IANA name from TZ or
+	// /etc/localtime or else by its offset
+	TimeZone string
+	// overrides HourCycle, true is "h11" or "h12" depending on the locale
+	Hour12 *bool
+	// "h11", "h12", "h23" or "h24"
+	HourCycle string
+	// "latn", "arab", ... defaults to the locale's numbering system
+	NumberingSystem string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat/resolvedOptions
+//
+// The fields are empty when a style is used, HourCycle and Hour12 are only
+// set when the hour is shown.
+type DateTimeFormatResolvedOptions struct {
+	Locale          string
+	Calendar        string
+	NumberingSystem string
+	TimeZone        string
+	HourCycle       string
+	Hour12          bool
+
+	DateStyle              string
+	TimeStyle              string
+	Weekday                string
+	Era                    string
+	Year                   string
+	Month                  string
+	Day                    string
+	Hour                   string
+	Minute                 string
+	Second                 string
+	FractionalSecondDigits int
+	TimeZoneName           string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat
+//
+// Only the gregorian calendar is implemented. Time zone names other than UTC
+// and the US zones in English are shown as GMT offsets.
+type DateTimeFormat struct {
+	options  DateTimeFormatResolvedOptions
+	data     *localeData
+	nf       *NumberFormat
+	location *time.Location
+	pattern  []dateToken
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat/DateTimeFormat
+func NewDateTimeFormat(tag string, options ...DateTimeFormatOptions) (*DateTimeFormat, error) {
+	var opts DateTimeFormatOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	l, data := resolveLocale(tag)
+	nf, err := NewNumberFormat(tag, NumberFormatOptions{NumberingSystem: opts.NumberingSystem})
+	if err != nil {
+		return nil, err
+	}
+	dtf := &DateTimeFormat{data: data, nf: nf}
+	resolved := &dtf.options
+	resolved.Locale = l.String()
+	resolved.Calendar = "gregory"
+	resolved.NumberingSystem = nf.options.NumberingSystem
+	if dtf.location, resolved.TimeZone, err = resolveTimeZone(opts.TimeZone); err != nil {
+		return nil, err
+	}
+
+	styles := []string{"", "full", "long", "medium", "short"}
+	textWidths := []string{"", "long", "short", "narrow"}
+	numericWidths := []string{"", "numeric", "2-digit"}
+	checks := []struct {
+		name    string
+		value   *string
+		allowed []string
+	}{
+		{"dateStyle", &opts.DateStyle, styles},
+		{"timeStyle", &opts.TimeStyle, styles},
+		{"weekday", &opts.Weekday, textWidths},
+		{"era", &opts.Era, textWidths},
+		{"year", &opts.Year, numericWidths},
+		{"month", &opts.Month, append(numericWidths, textWidths[1:]...)},
+		{"day", &opts.Day, numericWidths},
+		{"hour", &opts.Hour, numericWidths},
+		{"minute", &opts.Minute, numericWidths},
+		{"second", &opts.Second, numericWidths},
+		{"timeZoneName", &opts.TimeZoneName, []string{"", "short", "long", "shortOffset", "longOffset"}},
+		{"hourCycle", &opts.HourCycle, []string{"", "h11", "h12", "h23", "h24"}},
+	}
+	for _, check := range checks {
+		if *check.value, err = oneOf(check.name, *check.value, check.allowed...); err != nil {
+			return nil, err
+		}
+	}
+	if opts.FractionalSecondDigits < 0 || opts.FractionalSecondDigits > 3 {
+		return nil, fmt.Errorf("jsintl: fractionalSecondDigits value is out of range")
+	}
+
+	hourCycle := dtf.resolveHourCycle(l, opts)
+	hasStyle := opts.DateStyle != "" || opts.TimeStyle != ""
+	hasFields := opts.Weekday != "" || opts.Era != "" || opts.Year != "" || opts.Month != "" || opts.Day != "" ||
+		opts.Hour != "" || opts.Minute != "" || opts.Second != "" || opts.FractionalSecondDigits != 0 || opts.TimeZoneName != ""
+	var pattern string
+	switch {
+	case hasStyle && hasFields:
+		return nil, fmt.Errorf("jsintl: dateStyle and timeStyle can't be used with other date and time options")
+	case hasStyle:
+		resolved.DateStyle, resolved.TimeStyle = opts.DateStyle, opts.TimeStyle
+		pattern = dtf.stylePattern(opts.DateStyle, opts.TimeStyle, hourCycle)
+	default:
+		if opts.Weekday == "" && opts.Year == "" && opts.Month == "" && opts.Day == "" &&
+			opts.Hour == "" && opts.Minute == "" && opts.Second == "" && opts.FractionalSecondDigits == 0 {
+			opts.Year, opts.Month, opts.Day = "numeric", "numeric", "numeric"
+		}
+		pattern = dtf.skeletonPattern(opts, hourCycle)
+		resolved.TimeZoneName = opts.TimeZoneName
+		resolved.FractionalSecondDigits = opts.FractionalSecondDigits
+	}
+	dtf.pattern = parseDatePattern(pattern)
+
+	for _, token := range dtf.pattern {
+		switch token.field {
+		case 'h', 'H', 'K', 'k':
+			resolved.HourCycle = hourCycles[token.field]
+			resolved.Hour12 = token.field == 'h' || token.field == 'K'
+		}
+		if hasStyle {
+			continue
+		}
+		switch token.field {
+		case 'G':
+			resolved.Era = textWidth(token.width)
+		case 'E', 'c':
+			resolved.Weekday = textWidth(token.width)
+		case 'y':
+			resolved.Year = numericWidth(token.width)
+		case 'M', 'L':
+			if token.width >= 3 {
+				resolved.Month = textWidth(token.width)
+			} else {
+				resolved.Month = numericWidth(token.width)
+			}
+		case 'd':
+			resolved.Day = numericWidth(token.width)
+		case 'h', 'H', 'K', 'k':
+			resolved.Hour = numericWidth(token.width)
+		case 'm':
+			resolved.Minute = numericWidth(token.width)
+		case 's':
+			resolved.Second = numericWidth(token.width)
+		}
+	}
+	return dtf, nil
+}
+
+var hourCycles = map[byte]string{'K': "h11", 'h': "h12", 'H': "h23", 'k': "h24"}
+
+func hourLetter(hourCycle string) byte {
+	for letter, cycle := range hourCycles {
+		if cycle == hourCycle {
+			return letter
+		}
+	}
+	return 'H'
+}
+
+func textWidth(width int) string {
+	switch {
+	case width == 4:
+		return "long"
+	case width >= 5:
+		return "narrow"
+	}
+	return "short"
+}
+
+func numericWidth(width int) string {
+	if width == 2 {
+		return "2-digit"
+	}
+	return "numeric"
+}
+
+// resolveTimeZone accepts IANA names and UTC offsets
+func resolveTimeZone(name string) (*time.Location, string, error) {
+	switch {
+	case name == "":
+		location, name := localTimeZone()
+		return location, name, nil
+	case strings.EqualFold(name, "UTC"):
+		return time.UTC, "UTC", nil
+	case len(name) == 6 && (name[0] == '+' || name[0] == '-') && name[3] == ':':
+		hours, err1 := strconv.Atoi(name[1:3])
+		minutes, err2 := strconv.Atoi(name[4:])
+		if err1 != nil || err2 != nil || hours > 23 || minutes > 59 {
+			break
+		}
+		offset := hours*3600 + minutes*60
+		if name[0] == '-' {
+			offset = -offset
+		}
+		return time.FixedZone(name, offset), name, nil
+	default:
+		if location, err := time.LoadLocation(name); err == nil {
+			return location, location.String(), nil
+		}
+	}
+	return nil, "", fmt.Errorf("jsintl: invalid time zone %q", name)
+}
+
+// localTimeZone names the zone of time.Local like JS does, Go calls the zone
+// of /etc/localtime "Local" and a zone from TZ by its path or name. A zone
+// without an IANA name, like a copied /etc/localtime or a FixedZone, is named
+// by its current offset such as "+01:00".
+func localTimeZone() (*time.Location, string) {
+	name := time.Local.String()
+	if name == "Local" {
+		name, _ = os.Readlink("/etc/localtime")
+	}
+	if _, zone, found := strings.Cut(name, "zoneinfo/"); found {
+		name = zone
+	}
+	// the POSIX and leap second variants of the database share the names
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "posix/"), "right/")
+	if location, err := time.LoadLocation(name); err == nil && name != "" && name != "Local" {
+		return time.Local, location.String()
+	}
+	_, offset := time.Now().In(time.Local).Zone()
+	if offset == 0 {
+		return time.Local, "UTC"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return time.Local, fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset/60%60)
+}
+
+// resolveHourCycle picks hour12, hourCycle, the "-u-hc-" keyword and then the
+// locale default
+func (dtf *DateTimeFormat) resolveHourCycle(l locale, opts DateTimeFormatOptions) string {
+	twelve := "h12"
+	if strings.ContainsRune(dtf.data.Dates.AvailableFormats["h"], 'K') {
+		twelve = "h11"
+	}
+	switch {
+	case opts.Hour12 != nil && *opts.Hour12:
+		return twelve
+	case opts.Hour12 != nil:
+		return "h23"
+	case opts.HourCycle != "":
+		return opts.HourCycle
+	}
+	switch keyword := l.keywords["hc"]; keyword {
+	case "h11", "h12", "h23", "h24":
+		return keyword
+	}
+	if dtf.data.Dates.HourCycle == "h12" {
+		return twelve
+	}
+	return dtf.data.Dates.HourCycle
+}
+
+// stylePattern joins the dateStyle and timeStyle patterns, a time pattern
+// using the other kind of clock is rebuilt from its skeleton
+func (dtf *DateTimeFormat) stylePattern(dateStyle, timeStyle, hourCycle string) string {
+	dates := dtf.data.Dates
+	timePattern := dates.TimeFormats[timeStyle]
+	if timeStyle != "" {
+		letter := hourLetter(hourCycle)
+		tokens := parseDatePattern(timePattern)
+		rebuild := false
+		for idx, token := range tokens {
+			switch token.field {
+			case 'h', 'H', 'K', 'k':
+				if is12Hour(token.field) != is12Hour(letter) {
+					rebuild = true
+				}
+				tokens[idx].field = letter
+			}
+		}
+		timePattern = formatDatePattern(tokens)
+		if rebuild {
+			opts := DateTimeFormatOptions{Hour: "numeric", Minute: "2-digit"}
+			switch timeStyle {
+			case "full":
+				opts.Second, opts.TimeZoneName = "2-digit", "long"
+			case "long":
+				opts.Second, opts.TimeZoneName = "2-digit", "short"
+			case "medium":
+				opts.Second = "2-digit"
+			}
+			timePattern = dtf.skeletonPattern(opts, hourCycle)
+		}
+	}
+	switch {
+	case dateStyle == "":
+		return timePattern
+	case timeStyle == "":
+		return dates.DateFormats[dateStyle]
+	}
+	return joinDateTime(dates.DateTimeFormats[dateStyle], dates.DateFormats[dateStyle], timePattern)
+}
+
+func is12Hour(letter byte) bool {
+	return letter == 'h' || letter == 'K'
+}
+
+func joinDateTime(glue, date, time string) string {
+	return strings.NewReplacer("{1}", date, "{0}", time).Replace(glue)
+}
+
+// skeletonPattern builds the pattern for the field options from the closest
+// CLDR available format
+//
+// https://unicode.org/reports/tr35/tr35-dates.html#Matching_Skeletons
+func (dtf *DateTimeFormat) skeletonPattern(opts DateTimeFormatOptions, hourCycle string) string {
+	textWidths := map[string]int{"short": 1, "long": 4, "narrow": 5}
+	numericWidths := map[string]int{"numeric": 1, "2-digit": 2}
+	monthWidths := map[string]int{"numeric": 1, "2-digit": 2, "short": 3, "long": 4, "narrow": 5}
+
+	date := skeleton{}
+	if opts.Era != "" {
+		date['G'] = textWidths[opts.Era]
+	}
+	if opts.Year != "" {
+		date['y'] = numericWidths[opts.Year]
+	}
+	if opts.Month != "" {
+		date['M'] = monthWidths[opts.Month]
+	}
+	if opts.Day != "" {
+		date['d'] = numericWidths[opts.Day]
+	}
+	if opts.Weekday != "" {
+		date['E'] = textWidths[opts.Weekday]
+	}
+	clock := skeleton{}
+	if opts.Hour != "" {
+		clock['j'] = numericWidths[opts.Hour]
+	}
+	if opts.Minute != "" {
+		clock['m'] = numericWidths[opts.Minute]
+	}
+	if opts.Second != "" {
+		clock['s'] = numericWidths[opts.Second]
+	}
+
+	letter := hourLetter(hourCycle)
+	datePattern := dtf.matchSkeleton(date, letter)
+	timeTokens := parseDatePattern(dtf.matchSkeleton(clock, letter))
+	if n := opts.FractionalSecondDigits; n > 0 {
+		// the fraction follows the seconds, "1:05:09.123"
+		idx := len(timeTokens)
+		for i, token := range timeTokens {
+			if token.field == 's' {
+				idx = i + 1
+				break
+			}
+		}
+		fraction := []dateToken{{field: 'S', width: n}}
+		if idx > 0 {
+			fraction = append([]dateToken{{literal: dtf.nf.symbols.Decimal}}, fraction...)
+		}
+		timeTokens = append(timeTokens[:idx:idx], append(fraction, timeTokens[idx:]...)...)
+	}
+	if opts.TimeZoneName != "" {
+		zone := map[string]dateToken{
+			"short":       {field: 'z', width: 1},
+			"long":        {field: 'z', width: 4},
+			"shortOffset": {field: 'O', width: 1},
+			"longOffset":  {field: 'O', width: 4},
+		}[opts.TimeZoneName]
+		if len(timeTokens) > 0 {
+			timeTokens = append(timeTokens, dateToken{literal: " "})
+		}
+		timeTokens = append(timeTokens, zone)
+	}
+	timePattern := formatDatePattern(timeTokens)
+
+	switch {
+	case datePattern == "":
+		return timePattern
+	case timePattern == "":
+		return datePattern
+	}
+	style := "short"
+	switch {
+	case date['M'] == 4 && date['E'] != 0:
+		style = "full"
+	case date['M'] == 4:
+		style = "long"
+	case date['M'] == 3:
+		style = "medium"
+	}
+	return joinDateTime(dtf.data.Dates.DateTimeFormats[style], datePattern, timePattern)
+}
+
+// skeleton maps canonical field letters to widths, 'j' is the hour
+type skeleton map[byte]int
+
+func canonicalField(letter byte) byte {
+	switch letter {
+	case 'L':
+		return 'M'
+	case 'c':
+		return 'E'
+	case 'h', 'H', 'K', 'k':
+		return 'j'
+	}
+	return letter
+}
+
+// matchSkeleton finds the available format with the same fields and the
+// closest widths, then adjusts its widths to the request
+func (dtf *DateTimeFormat) matchSkeleton(requested skeleton, hourLetter byte) string {
+	if len(requested) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(dtf.data.Dates.AvailableFormats))
+	for key := range dtf.data.Dates.AvailableFormats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	best, bestDistance := "", -1
+	for _, key := range keys {
+		fields := skeleton{}
+		for _, token := range parseDatePattern(key) {
+			fields[canonicalField(token.field)] = token.width
+			if canonicalField(token.field) == 'j' && is12Hour(token.field) != is12Hour(hourLetter) {
+				fields = nil
+				break
+			}
+		}
+		if len(fields) != len(requested) {
+			continue
+		}
+		distance := 0
+		for field, width := range requested {
+			keyWidth, ok := fields[field]
+			if !ok {
+				distance = -1
+				break
+			}
+			if (keyWidth >= 3) != (width >= 3) {
+				distance += 100
+			}
+			distance += abs(keyWidth - width)
+		}
+		if distance >= 0 && (bestDistance < 0 || distance < bestDistance) {
+			best, bestDistance = key, distance
+		}
+	}
+
+	var tokens []dateToken
+	if best == "" {
+		// no format has these fields, list them in the usual order
+		for _, field := range []byte("GyMdEjms") {
+			width, ok := requested[field]
+			if !ok {
+				continue
+			}
+			if len(tokens) > 0 {
+				separator := " "
+				if field == 'm' || field == 's' {
+					separator = ":"
+				}
+				tokens = append(tokens, dateToken{literal: separator})
+			}
+			tokens = append(tokens, dateToken{field: field, width: width})
+		}
+	} else {
+		tokens = parseDatePattern(dtf.data.Dates.AvailableFormats[best])
+	}
+	for idx, token := range tokens {
+		field := canonicalField(token.field)
+		width, ok := requested[field]
+		if field == 0 || !ok {
+			continue
+		}
+		switch field {
+		case 'M':
+			// "M月" stays numeric even when a month name is requested
+			if (width >= 3) == (token.width >= 3) {
+				if width >= 3 {
+					tokens[idx].width = width
+				} else {
+					tokens[idx].width = max(token.width, width)
+				}
+			}
+		case 'E', 'G', 'y':
+			tokens[idx].width = width
+		default:
+			tokens[idx].width = max(token.width, width)
+		}
+		if field == 'j' {
+			tokens[idx].field = hourLetter
+		}
+	}
+	return formatDatePattern(tokens)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat/resolvedOptions
+func (dtf *DateTimeFormat) ResolvedOptions() DateTimeFormatResolvedOptions {
+	return dtf.options
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat/format
+func (dtf *DateTimeFormat) Format(t time.Time) string {
+	var sb strings.Builder
+	for _, part := range dtf.FormatToParts(t) {
+		sb.WriteString(part.Value)
+	}
+	return sb.String()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat/formatToParts
+func (dtf *DateTimeFormat) FormatToParts(t time.Time) []Part {
+	t = t.In(dtf.location)
+	dates := dtf.data.Dates
+	var parts []Part
+	add := func(partType, value string) {
+		if n := len(parts); partType == "literal" && n > 0 && parts[n-1].Type == "literal" {
+			parts[n-1].Value += value
+			return
+		}
+		parts = append(parts, Part{partType, value})
+	}
+	number := func(partType string, value, width int) {
+		add(partType, dtf.nf.localizeDigits(fmt.Sprintf("%0*d", width, value)))
+	}
+	year := t.Year()
+	era := 1
+	if year <= 0 {
+		era, year = 0, 1-year
+	}
+	for _, token := range dtf.pattern {
+		switch token.field {
+		case 0:
+			if token.literal != "" {
+				add("literal", token.literal)
+			}
+		case 'G':
+			add("era", dates.Eras[era])
+		case 'y':
+			if token.width == 2 {
+				number("year", year%100, 2)
+			} else {
+				number("year", year, token.width)
+			}
+		case 'M', 'L':
+			if token.width < 3 {
+				number("month", int(t.Month()), token.width)
+			} else {
+				add("month", dates.Months[nameWidth(token.width)][t.Month()-1])
+			}
+		case 'd':
+			number("day", t.Day(), token.width)
+		case 'E', 'c':
+			add("weekday", dates.Weekdays[nameWidth(max(token.width, 3))][t.Weekday()])
+		case 'a':
+			add("dayPeriod", dates.DayPeriods[t.Hour()/12])
+		case 'h', 'H', 'K', 'k':
+			hour := t.Hour()
+			switch token.field {
+			case 'h':
+				hour = (hour+11)%12 + 1
+			case 'K':
+				hour %= 12
+			case 'k':
+				if hour == 0 {
+					hour = 24
+				}
+			}
+			number("hour", hour, token.width)
+		case 'm':
+			number("minute", t.Minute(), token.width)
+		case 's':
+			number("second", t.Second(), token.width)
+		case 'S':
+			add("fractionalSecond", dtf.nf.localizeDigits(fmt.Sprintf("%09d", t.Nanosecond())[:min(token.width, 9)]))
+		case 'z', 'O':
+			add("timeZoneName", dtf.zoneName(t, token))
+		default:
+			add("literal", strings.Repeat(string(token.field), token.width))
+		}
+	}
+	return parts
+}
+
+func nameWidth(width int) string {
+	switch width {
+	case 4:
+		return "wide"
+	case 5:
+		return "narrow"
+	}
+	return "abbreviated"
+}
+
+// the zones with English names, their abbreviations are ambiguous elsewhere
+// so the offset has to match too ("CST" is also China Standard Time)
+var englishZoneNames = map[string]struct {
+	offset int
+	name   string
+}{
+	"UTC":  {0, "Coordinated Universal Time"},
+	"EST":  {-5 * 3600, "Eastern Standard Time"},
+	"EDT":  {-4 * 3600, "Eastern Daylight Time"},
+	"CST":  {-6 * 3600, "Central Standard Time"},
+	"CDT":  {-5 * 3600, "Central Daylight Time"},
+	"MST":  {-7 * 3600, "Mountain Standard Time"},
+	"MDT":  {-6 * 3600, "Mountain Daylight Time"},
+	"PST":  {-8 * 3600, "Pacific Standard Time"},
+	"PDT":  {-7 * 3600, "Pacific Daylight Time"},
+	"AKST": {-9 * 3600, "Alaska Standard Time"},
+	"AKDT": {-8 * 3600, "Alaska Daylight Time"},
+	"HST":  {-10 * 3600, "Hawaii-Aleutian Standard Time"},
+}
+
+func (dtf *DateTimeFormat) zoneName(t time.Time, token dateToken) string {
+	abbreviation, offset := t.Zone()
+	if token.field == 'z' {
+		known, ok := englishZoneNames[abbreviation]
+		switch {
+		case abbreviation == "UTC" && offset == 0 && token.width < 4:
+			return "UTC"
+		case ok && known.offset == offset && strings.HasPrefix(dtf.options.Locale, "en"):
+			if token.width < 4 {
+				return abbreviation
+			}
+			return known.name
+		}
+	}
+	if offset == 0 {
+		return strings.Replace(dtf.data.Dates.GMTFormat, "{0}", "", 1)
+	}
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	hours, minutes := offset/3600, offset/60%60
+	var value string
+	switch {
+	case token.width >= 4:
+		value = fmt.Sprintf("%s%02d:%02d", sign, hours, minutes)
+	case minutes != 0:
+		value = fmt.Sprintf("%s%d:%02d", sign, hours, minutes)
+	default:
+		value = fmt.Sprintf("%s%d", sign, hours)
+	}
+	return strings.Replace(dtf.data.Dates.GMTFormat, "{0}", value, 1)
+}
+
+// dateToken is a field such as "MMM" or literal text of a CLDR date pattern
+//
+// https://unicode.org/reports/tr35/tr35-dates.html#Date_Field_Symbol_Table
+type dateToken struct {
+	field   byte
+	width   int
+	literal string
+}
+
+func parseDatePattern(pattern string) []dateToken {
+	var tokens []dateToken
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			tokens = append(tokens, dateToken{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+	quoted := false
+	for idx := 0; idx < len(pattern); idx++ {
+		c := pattern[idx]
+		switch {
+		case c == '\'':
+			if idx+1 < len(pattern) && pattern[idx+1] == '\'' {
+				literal.WriteByte('\'')
+				idx++
+			} else {
+				quoted = !quoted
+			}
+		case !quoted && (c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'):
+			flush()
+			width := 1
+			for idx+1 < len(pattern) && pattern[idx+1] == c {
+				width++
+				idx++
+			}
+			tokens = append(tokens, dateToken{field: c, width: width})
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+	return tokens
+}
+
+func formatDatePattern(tokens []dateToken) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		if token.field != 0 {
+			sb.WriteString(strings.Repeat(string(token.field), token.width))
+		} else if token.literal != "" {
+			sb.WriteString("'" + strings.ReplaceAll(token.literal, "'", "''") + "'")
+		}
+	}
+	return sb.String()
+}
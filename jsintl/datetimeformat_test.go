@@ -0,0 +1,117 @@
+package jsintl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	// the tests need the zones on machines without a zoneinfo database
+	_ "time/tzdata"
+)
+
+var testDate = time.Date(2024, 1, 5, 13, 5, 9, 123456789, time.UTC)
+
+func TestDateTimeFormat(t *testing.T) {
+	cases := []struct {
+		locale  string
+		options DateTimeFormatOptions
+		expect  string
+	}{
+		{"en-US", DateTimeFormatOptions{}, "1/5/2024"},
+		{"de", DateTimeFormatOptions{}, "5.1.2024"},
+		{"ja", DateTimeFormatOptions{}, "2024/1/5"},
+		{"zh-CN", DateTimeFormatOptions{}, "2024/1/5"},
+		{"en", DateTimeFormatOptions{DateStyle: "full", TimeStyle: "short"}, "Friday, January 5, 2024 at 1:05\u202fPM"},
+		{"de", DateTimeFormatOptions{DateStyle: "full", TimeStyle: "medium"}, "Freitag, 5. Januar 2024 um 13:05:09"},
+		{"fr", DateTimeFormatOptions{DateStyle: "short", TimeStyle: "short"}, "05/01/2024 13:05"},
+		{"de", DateTimeFormatOptions{TimeStyle: "short", Hour12: Bool(true)}, "1:05 PM"},
+		{"en", DateTimeFormatOptions{Year: "2-digit", Month: "2-digit", Day: "2-digit"}, "01/05/24"},
+		{"en", DateTimeFormatOptions{Weekday: "long", Year: "numeric", Month: "long", Day: "numeric"}, "Friday, January 5, 2024"},
+		{"en", DateTimeFormatOptions{Month: "long", Day: "numeric", Hour: "numeric"}, "January 5 at 1\u202fPM"},
+		{"en", DateTimeFormatOptions{Hour: "numeric", Minute: "numeric", Second: "numeric", FractionalSecondDigits: 3}, "1:05:09.123\u202fPM"},
+		{"en", DateTimeFormatOptions{Hour: "2-digit", Minute: "2-digit", HourCycle: "h23"}, "13:05"},
+		{"ja", DateTimeFormatOptions{Hour: "numeric", Hour12: Bool(true)}, "午後1時"},
+		{"ja", DateTimeFormatOptions{Year: "numeric", Month: "short", Day: "numeric", Weekday: "short"}, "2024年1月5日(金)"},
+		{"en", DateTimeFormatOptions{Era: "short", Year: "numeric"}, "2024 AD"},
+		{"en", DateTimeFormatOptions{TimeZoneName: "short"}, "1/5/2024, UTC"},
+		{"en", DateTimeFormatOptions{DateStyle: "medium", TimeStyle: "full", TimeZone: "America/Los_Angeles"}, "Jan 5, 2024, 5:05:09\u202fAM Pacific Standard Time"},
+		{"en", DateTimeFormatOptions{Hour: "numeric", Minute: "numeric", TimeZone: "Asia/Tokyo", TimeZoneName: "shortOffset"}, "10:05\u202fPM GMT+9"},
+		{"fr", DateTimeFormatOptions{Hour: "numeric", Minute: "numeric", TimeZone: "+05:30", TimeZoneName: "longOffset"}, "18:35 UTC+05:30"},
+		{"ar-u-nu-arab", DateTimeFormatOptions{}, "٥\u200f/١\u200f/٢٠٢٤"},
+	}
+	for _, c := range cases {
+		if c.options.TimeZone == "" {
+			c.options.TimeZone = "UTC"
+		}
+		dtf, err := NewDateTimeFormat(c.locale, c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := dtf.Format(testDate); result != c.expect {
+			t.Errorf("%s %+v: expect %q, got %q", c.locale, c.options, c.expect, result)
+		}
+	}
+}
+
+func TestDateTimeFormatToParts(t *testing.T) {
+	dtf, err := NewDateTimeFormat("en", DateTimeFormatOptions{DateStyle: "medium", TimeStyle: "short", TimeZone: "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []Part{
+		{"month", "Jan"}, {"literal", " "}, {"day", "5"}, {"literal", ", "}, {"year", "2024"}, {"literal", ", "},
+		{"hour", "1"}, {"literal", ":"}, {"minute", "05"}, {"literal", "\u202f"}, {"dayPeriod", "PM"},
+	}
+	if parts := dtf.FormatToParts(testDate); !reflect.DeepEqual(parts, expect) {
+		t.Fatalf("expect %v, got %v", expect, parts)
+	}
+}
+
+func TestDateTimeFormatOptions(t *testing.T) {
+	t.Run("resolved", func(t *testing.T) {
+		dtf, err := NewDateTimeFormat("en-US", DateTimeFormatOptions{Hour: "numeric", Minute: "numeric", TimeZone: "Asia/Shanghai"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		options := dtf.ResolvedOptions()
+		if options.Locale != "en-US" || options.TimeZone != "Asia/Shanghai" || options.HourCycle != "h12" ||
+			!options.Hour12 || options.Minute != "2-digit" || options.Year != "" {
+			t.Fatalf("unexpected resolved options %+v", options)
+		}
+	})
+	t.Run("local time zone", func(t *testing.T) {
+		defer func(local *time.Location) { time.Local = local }(time.Local)
+		tokyo, _ := time.LoadLocation("Asia/Tokyo")
+		for _, c := range []struct {
+			local *time.Location
+			want  string
+		}{
+			{tokyo, "Asia/Tokyo"},
+			{time.FixedZone("", 3600), "+01:00"},
+			{time.FixedZone("", -(5*3600 + 30*60)), "-05:30"},
+			{time.FixedZone("", 0), "UTC"},
+			{time.FixedZone("/usr/share/zoneinfo/posix/Europe/Berlin", 3600), "Europe/Berlin"},
+		} {
+			time.Local = c.local
+			dtf, _ := NewDateTimeFormat("en", DateTimeFormatOptions{Hour: "numeric", HourCycle: "h23"})
+			if got := dtf.ResolvedOptions().TimeZone; got != c.want {
+				t.Fatalf("expect %q, got %q", c.want, got)
+			}
+			if got, want := dtf.Format(testDate), testDate.In(c.local).Format("15"); got != want {
+				t.Fatalf("expect the hour in the local zone %s, got %s", want, got)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		invalid := []DateTimeFormatOptions{
+			{DateStyle: "short", Hour: "numeric"},
+			{Month: "full"},
+			{FractionalSecondDigits: 4},
+			{TimeZone: "Mars/Olympus_Mons"},
+		}
+		for _, options := range invalid {
+			if _, err := NewDateTimeFormat("en", options); err == nil {
+				t.Errorf("expect an error for %+v", options)
+			}
+		}
+	})
+}
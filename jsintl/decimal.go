@@ -0,0 +1,110 @@
+package jsintl
+
+import (
+	"strconv"
+	"strings"
+)
+
+// decimal is a non-negative finite number kept as decimal digits, the value is
+// 0.d1d2d3... × 10^exp, so rounding works on the digits a user sees instead of
+// on the binary float
+type decimal struct {
+	// '0'-'9' without leading or trailing zeros, empty for zero
+	digits []byte
+	exp    int
+}
+
+// newDecimal uses the shortest representation of v, the same digits ICU
+// starts from, so 1.005 rounds to 1.01 like it does in a browser
+func newDecimal(v float64) decimal {
+	if v == 0 {
+		return decimal{}
+	}
+	s := strconv.FormatFloat(v, 'e', -1, 64)
+	mantissa, exponent, _ := strings.Cut(s, "e")
+	exp, _ := strconv.Atoi(exponent)
+	digits := []byte(strings.Replace(mantissa, ".", "", 1))
+	d := decimal{digits: digits, exp: exp + 1}
+	d.trim()
+	return d
+}
+
+func (d decimal) isZero() bool {
+	return len(d.digits) == 0
+}
+
+// magnitude is the power of ten of the leading digit, 0 for zero
+func (d decimal) magnitude() int {
+	if d.isZero() {
+		return 0
+	}
+	return d.exp - 1
+}
+
+// shift multiplies d by 10^n
+func (d decimal) shift(n int) decimal {
+	if d.isZero() {
+		return d
+	}
+	return decimal{digits: d.digits, exp: d.exp + n}
+}
+
+// round keeps the digits worth 10^position or more, rounding half away from
+// zero ("halfExpand", the JS default)
+func (d decimal) round(position int) decimal {
+	keep := d.exp - position
+	if keep >= len(d.digits) {
+		return d
+	}
+	if keep < 0 {
+		return decimal{}
+	}
+	roundUp := d.digits[keep] >= '5'
+	digits := make([]byte, keep)
+	copy(digits, d.digits[:keep])
+	result := decimal{digits: digits, exp: d.exp}
+	if roundUp {
+		idx := keep - 1
+		for idx >= 0 && result.digits[idx] == '9' {
+			idx--
+		}
+		if idx < 0 {
+			result.digits = []byte{'1'}
+			result.exp++
+		} else {
+			result.digits[idx]++
+			result.digits = result.digits[:idx+1]
+		}
+	}
+	result.trim()
+	return result
+}
+
+func (d *decimal) trim() {
+	d.digits = []byte(strings.TrimRight(string(d.digits), "0"))
+	if len(d.digits) == 0 {
+		*d = decimal{}
+	}
+}
+
+// strings returns the integer and fraction digits, padded to the minimums
+func (d decimal) strings(minInteger, minFraction int) (string, string) {
+	var integer, fraction string
+	switch {
+	case d.isZero():
+	case d.exp <= 0:
+		fraction = strings.Repeat("0", -d.exp) + string(d.digits)
+	case d.exp >= len(d.digits):
+		integer = string(d.digits) + strings.Repeat("0", d.exp-len(d.digits))
+	default:
+		integer = string(d.digits[:d.exp])
+		fraction = string(d.digits[d.exp:])
+	}
+	if len(integer) < minInteger {
+		integer = strings.Repeat("0", minInteger-len(integer)) + integer
+	}
+	if len(fraction) < minFraction {
+		fraction += strings.Repeat("0", minFraction-len(fraction))
+	}
+	return integer, fraction
+}
@@ -0,0 +1,149 @@
+package jsintl
+
+import (
+	"embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// the locale data is a small subset of CLDR (https://github.com/unicode-org/cldr-json)
+// generated for the locales below
+//
+//go:embed data/*.json
+var dataFS embed.FS
+
+const defaultLocale = "en"
+
+var supportedLanguages = []string{"ar", "de", "en", "fr", "ja", "zh"}
+
+type localeData struct {
+	Numbers numbersData `json:"numbers"`
+	Plurals struct {
+		Cardinal map[string]string `json:"cardinal"`
+		Ordinal  map[string]string `json:"ordinal"`
+	} `json:"plurals"`
+	Dates datesData `json:"dates"`
+	// style => unit => patterns
+	RelativeTime map[string]map[string]relativeTimeData `json:"relativeTime"`
+
+	cardinal pluralRuleSet
+	ordinal  pluralRuleSet
+}
+
+var (
+	dataMutex sync.Mutex
+	dataCache = map[string]*localeData{}
+)
+
+// loadLocaleData returns the parsed data of a supported language, the
+// embedded files are part of the package so a broken one panics
+func loadLocaleData(language string) *localeData {
+	dataMutex.Lock()
+	defer dataMutex.Unlock()
+	if data, ok := dataCache[language]; ok {
+		return data
+	}
+	buf, err := dataFS.ReadFile("data/" + language + ".json")
+	if err != nil {
+		panic("jsintl: missing locale data for " + language)
+	}
+	data := &localeData{}
+	if err := json.Unmarshal(buf, data); err != nil {
+		panic("jsintl: broken locale data for " + language + ": " + err.Error())
+	}
+	if data.cardinal, err = parsePluralRuleSet(data.Plurals.Cardinal); err != nil {
+		panic("jsintl: broken plural rules for " + language + ": " + err.Error())
+	}
+	if data.ordinal, err = parsePluralRuleSet(data.Plurals.Ordinal); err != nil {
+		panic("jsintl: broken plural rules for " + language + ": " + err.Error())
+	}
+	dataCache[language] = data
+	return data
+}
+
+// locale is the part of a BCP 47 language tag jsintl understands
+type locale struct {
+	language string
+	script   string
+	region   string
+	// unicode extension keywords, e.g. "nu" => "arab" for "ar-u-nu-arab"
+	keywords map[string]string
+}
+
+func parseLocale(tag string) locale {
+	l := locale{keywords: map[string]string{}}
+	subtags := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	l.language = strings.ToLower(subtags[0])
+	for idx := 1; idx < len(subtags); idx++ {
+		subtag := subtags[idx]
+		switch {
+		case strings.EqualFold(subtag, "u"):
+			for idx+2 < len(subtags) && len(subtags[idx+1]) == 2 {
+				l.keywords[strings.ToLower(subtags[idx+1])] = strings.ToLower(subtags[idx+2])
+				idx += 2
+			}
+			return l
+		case len(subtag) == 1:
+			// other extensions and private use are ignored
+			return l
+		case len(subtag) == 4 && l.script == "" && l.region == "":
+			l.script = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		case (len(subtag) == 2 || len(subtag) == 3 && isDigits(subtag)) && l.region == "":
+			l.region = strings.ToUpper(subtag)
+		}
+	}
+	return l
+}
+
+// String returns the canonical tag without extensions
+func (l locale) String() string {
+	parts := []string{l.language}
+	if l.script != "" {
+		parts = append(parts, l.script)
+	}
+	if l.region != "" {
+		parts = append(parts, l.region)
+	}
+	return strings.Join(parts, "-")
+}
+
+func isSupported(language string) bool {
+	for _, supported := range supportedLanguages {
+		if supported == language {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLocale does the "lookup" locale matching, an empty or unsupported
+// tag resolves to the default locale like a JS engine does
+func resolveLocale(tag string) (locale, *localeData) {
+	l := parseLocale(tag)
+	if !isSupported(l.language) {
+		l = locale{language: defaultLocale, keywords: l.keywords}
+	}
+	return l, loadLocaleData(l.language)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat/supportedLocalesOf
+func SupportedLocalesOf(locales ...string) []string {
+	var result []string
+	for _, tag := range locales {
+		l := parseLocale(tag)
+		if isSupported(l.language) {
+			result = append(result, l.String())
+		}
+	}
+	return result
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
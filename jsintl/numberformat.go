@@ -0,0 +1,825 @@
+package jsintl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type numbersData struct {
+	DefaultNumberingSystem string                                  `json:"defaultNumberingSystem"`
+	RegionNumberingSystems map[string]string                       `json:"regionNumberingSystems"`
+	MinimumGroupingDigits  int                                     `json:"minimumGroupingDigits"`
+	Symbols                map[string]numberSymbols                `json:"symbols"`
+	Patterns               map[string]numberPatterns               `json:"patterns"`
+	Compact                map[string]map[string]map[string]string `json:"compact"`
+	Currencies             map[string]currencyData                 `json:"currencies"`
+	CurrencyUnitPattern    map[string]string                       `json:"currencyUnitPattern"`
+	Units                  map[string]map[string]map[string]string `json:"units"`
+}
+
+type numberSymbols struct {
+	Decimal     string `json:"decimal"`
+	Group       string `json:"group"`
+	PercentSign string `json:"percentSign"`
+	PlusSign    string `json:"plusSign"`
+	MinusSign   string `json:"minusSign"`
+	Exponential string `json:"exponential"`
+	Infinity    string `json:"infinity"`
+	NaN         string `json:"nan"`
+}
+
+type numberPatterns struct {
+	Decimal    string `json:"decimal"`
+	Percent    string `json:"percent"`
+	Currency   string `json:"currency"`
+	Accounting string `json:"accounting"`
+	Scientific string `json:"scientific"`
+}
+
+type currencyData struct {
+	Symbol string            `json:"symbol"`
+	Narrow string            `json:"narrow"`
+	Names  map[string]string `json:"names"`
+}
+
+// the digits of the numbering systems jsintl can render
+var numberingSystemDigits = map[string]string{
+	"latn":     "0123456789",
+	"arab":     "٠١٢٣٤٥٦٧٨٩",
+	"arabext":  "۰۱۲۳۴۵۶۷۸۹",
+	"fullwide": "０１２３４５６７８９",
+	"hanidec":  "〇一二三四五六七八九",
+}
+
+// ISO 4217 minor units that differ from the usual 2
+var currencyDigits = map[string]int{
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0,
+	"KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3, "VND": 0,
+}
+
+// Part is one token of a formatted string, as returned by formatToParts
+type Part struct {
+	Type  string
+	Value string
+}
+
+// Int returns a pointer to v, for the optional digit options
+func Int(v int) *int {
+	return &v
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat/NumberFormat#options
+//
+// Zero values mean the option is not set, the digit options are pointers
+// because 0 is a meaningful value for them.
+type NumberFormatOptions struct {
+	// "decimal" (default), "percent", "currency" or "unit"
+	Style string
+	// ISO 4217 code, required for the currency style
+	Currency string
+	// "symbol" (default), "narrowSymbol", "code" or "name"
+	CurrencyDisplay string
+	// "standard" (default) or "accounting"
+	CurrencySign string
+	// a sanctioned unit identifier such as "kilometer", required for the unit style
+	Unit string
+	// "short" (default), "long" or "narrow"
+	UnitDisplay string
+	// "standard" (default), "scientific", "engineering" or "compact"
+	Notation string
+	// "short" (default) or "long"
+	CompactDisplay string
+	// "auto" (default, "min2" for compact), "always", "min2", "true" or "false"
+	UseGrouping string
+	// "auto" (default), "always", "exceptZero", "negative" or "never"
+	SignDisplay string
+	// "latn", "arab", ... defaults to the locale's numbering system
+	NumberingSystem string
+
+	MinimumIntegerDigits     int
+	MinimumFractionDigits    *int
+	MaximumFractionDigits    *int
+	MinimumSignificantDigits *int
+	MaximumSignificantDigits *int
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat/resolvedOptions
+type NumberFormatResolvedOptions struct {
+	Locale          string
+	NumberingSystem string
+	Style           string
+	Currency        string
+	CurrencyDisplay string
+	CurrencySign    string
+	Unit            string
+	UnitDisplay     string
+	Notation        string
+	CompactDisplay  string
+	UseGrouping     string
+	SignDisplay     string
+	// "auto" when significant digits win, "morePrecision" for compact defaults
+	RoundingPriority string
+
+	MinimumIntegerDigits     int
+	MinimumFractionDigits    int
+	MaximumFractionDigits    int
+	MinimumSignificantDigits int
+	MaximumSignificantDigits int
+}
+
+type roundingType int
+
+const (
+	roundingFractionDigits roundingType = iota
+	roundingSignificantDigits
+	roundingMorePrecision
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat
+//
+// Only the "halfExpand" rounding mode is implemented.
+type NumberFormat struct {
+	options  NumberFormatResolvedOptions
+	data     *localeData
+	symbols  numberSymbols
+	digits   []string
+	pattern  numberPattern
+	decimal  numberPattern
+	rounding roundingType
+	// minimum integer digits before grouping kicks in, 0 disables grouping
+	minGrouping int
+	currency    currencyData
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat/NumberFormat
+func NewNumberFormat(tag string, options ...NumberFormatOptions) (*NumberFormat, error) {
+	var opts NumberFormatOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	l, data := resolveLocale(tag)
+	nf := &NumberFormat{data: data}
+	resolved := &nf.options
+	resolved.Locale = l.String()
+
+	var err error
+	if resolved.Style, err = oneOf("style", opts.Style, "decimal", "percent", "currency", "unit"); err != nil {
+		return nil, err
+	}
+	if resolved.Notation, err = oneOf("notation", opts.Notation, "standard", "scientific", "engineering", "compact"); err != nil {
+		return nil, err
+	}
+	if resolved.SignDisplay, err = oneOf("signDisplay", opts.SignDisplay, "auto", "always", "exceptZero", "negative", "never"); err != nil {
+		return nil, err
+	}
+	if resolved.Notation == "compact" {
+		if resolved.CompactDisplay, err = oneOf("compactDisplay", opts.CompactDisplay, "short", "long"); err != nil {
+			return nil, err
+		}
+	}
+	defaultGrouping := "auto"
+	if resolved.Notation == "compact" {
+		defaultGrouping = "min2"
+	}
+	if resolved.UseGrouping, err = oneOf("useGrouping", opts.UseGrouping, defaultGrouping, "auto", "always", "min2", "true", "false"); err != nil {
+		return nil, err
+	}
+	if resolved.UseGrouping == "true" {
+		resolved.UseGrouping = "always"
+	}
+
+	resolved.NumberingSystem = nf.resolveNumberingSystem(l, opts.NumberingSystem)
+	// numbering systems without their own data use the default symbols
+	symbols, ok := data.Numbers.Symbols[resolved.NumberingSystem]
+	if !ok {
+		symbols = data.Numbers.Symbols[data.Numbers.DefaultNumberingSystem]
+	}
+	nf.symbols = symbols
+	patterns, ok := data.Numbers.Patterns[resolved.NumberingSystem]
+	if !ok {
+		patterns = data.Numbers.Patterns[data.Numbers.DefaultNumberingSystem]
+	}
+	nf.digits = strings.Split(numberingSystemDigits[resolved.NumberingSystem], "")
+	nf.decimal = parseNumberPattern(patterns.Decimal)
+
+	mnfdDefault, mxfdDefault := 0, 3
+	switch resolved.Style {
+	case "decimal":
+		nf.pattern = nf.decimal
+	case "percent":
+		nf.pattern = parseNumberPattern(patterns.Percent)
+		mxfdDefault = 0
+	case "currency":
+		if len(opts.Currency) != 3 {
+			return nil, fmt.Errorf("jsintl: currency code is required with currency style, got %q", opts.Currency)
+		}
+		resolved.Currency = strings.ToUpper(opts.Currency)
+		if resolved.CurrencyDisplay, err = oneOf("currencyDisplay", opts.CurrencyDisplay, "symbol", "narrowSymbol", "code", "name"); err != nil {
+			return nil, err
+		}
+		if resolved.CurrencySign, err = oneOf("currencySign", opts.CurrencySign, "standard", "accounting"); err != nil {
+			return nil, err
+		}
+		nf.currency, ok = data.Numbers.Currencies[resolved.Currency]
+		if !ok {
+			nf.currency = currencyData{Symbol: resolved.Currency, Narrow: resolved.Currency}
+		}
+		switch {
+		case resolved.CurrencyDisplay == "name":
+			nf.pattern = nf.decimal
+		case resolved.CurrencySign == "accounting":
+			nf.pattern = parseNumberPattern(patterns.Accounting)
+		default:
+			nf.pattern = parseNumberPattern(patterns.Currency)
+		}
+		digits, ok := currencyDigits[resolved.Currency]
+		if !ok {
+			digits = 2
+		}
+		mnfdDefault, mxfdDefault = digits, digits
+	case "unit":
+		if opts.Unit == "" {
+			return nil, fmt.Errorf("jsintl: unit is required with unit style")
+		}
+		if _, ok := data.Numbers.Units[opts.Unit]; !ok {
+			return nil, fmt.Errorf("jsintl: unsupported unit %q", opts.Unit)
+		}
+		resolved.Unit = opts.Unit
+		if resolved.UnitDisplay, err = oneOf("unitDisplay", opts.UnitDisplay, "short", "long", "narrow"); err != nil {
+			return nil, err
+		}
+		nf.pattern = nf.decimal
+	}
+
+	if err := nf.resolveDigitOptions(opts, mnfdDefault, mxfdDefault); err != nil {
+		return nil, err
+	}
+	switch resolved.UseGrouping {
+	case "always":
+		nf.minGrouping = 1
+	case "min2":
+		nf.minGrouping = 2
+	case "auto":
+		nf.minGrouping = max(data.Numbers.MinimumGroupingDigits, 1)
+	}
+	return nf, nil
+}
+
+func (nf *NumberFormat) resolveNumberingSystem(l locale, option string) string {
+	for _, candidate := range []string{option, l.keywords["nu"], nf.data.Numbers.RegionNumberingSystems[l.region]} {
+		if _, ok := numberingSystemDigits[candidate]; ok {
+			return candidate
+		}
+	}
+	return nf.data.Numbers.DefaultNumberingSystem
+}
+
+// resolveDigitOptions follows SetNumberFormatDigitOptions of ECMA-402
+//
+// https://tc39.es/ecma402/#sec-setnfdigitoptions
+func (nf *NumberFormat) resolveDigitOptions(opts NumberFormatOptions, mnfdDefault, mxfdDefault int) error {
+	resolved := &nf.options
+	resolved.RoundingPriority = "auto"
+	resolved.MinimumIntegerDigits = 1
+	if opts.MinimumIntegerDigits != 0 {
+		if opts.MinimumIntegerDigits < 1 || opts.MinimumIntegerDigits > 21 {
+			return fmt.Errorf("jsintl: minimumIntegerDigits value is out of range")
+		}
+		resolved.MinimumIntegerDigits = opts.MinimumIntegerDigits
+	}
+	if resolved.Notation == "scientific" || resolved.Notation == "engineering" {
+		mnfdDefault, mxfdDefault = 0, 3
+	}
+	hasSd := opts.MinimumSignificantDigits != nil || opts.MaximumSignificantDigits != nil
+	hasFd := opts.MinimumFractionDigits != nil || opts.MaximumFractionDigits != nil
+	needSd := hasSd
+	needFd := !hasSd && (hasFd || resolved.Notation != "compact")
+
+	if needSd {
+		mnsd, err := digitOption("minimumSignificantDigits", opts.MinimumSignificantDigits, 1, 21, 1)
+		if err != nil {
+			return err
+		}
+		mxsd, err := digitOption("maximumSignificantDigits", opts.MaximumSignificantDigits, mnsd, 21, 21)
+		if err != nil {
+			return err
+		}
+		resolved.MinimumSignificantDigits, resolved.MaximumSignificantDigits = mnsd, mxsd
+		nf.rounding = roundingSignificantDigits
+		return nil
+	}
+	if needFd {
+		mnfd, mxfd := mnfdDefault, mxfdDefault
+		if hasFd {
+			var err error
+			if opts.MaximumFractionDigits != nil {
+				if mxfd, err = digitOption("maximumFractionDigits", opts.MaximumFractionDigits, 0, 100, 0); err != nil {
+					return err
+				}
+			}
+			if opts.MinimumFractionDigits != nil {
+				if mnfd, err = digitOption("minimumFractionDigits", opts.MinimumFractionDigits, 0, 100, 0); err != nil {
+					return err
+				}
+			}
+			switch {
+			case opts.MinimumFractionDigits == nil:
+				mnfd = min(mnfdDefault, mxfd)
+			case opts.MaximumFractionDigits == nil:
+				mxfd = max(mxfdDefault, mnfd)
+			case mnfd > mxfd:
+				return fmt.Errorf("jsintl: minimumFractionDigits is greater than maximumFractionDigits")
+			}
+		}
+		resolved.MinimumFractionDigits, resolved.MaximumFractionDigits = mnfd, mxfd
+		nf.rounding = roundingFractionDigits
+		return nil
+	}
+	// compact notation without any digit option
+	resolved.MinimumFractionDigits, resolved.MaximumFractionDigits = 0, 0
+	resolved.MinimumSignificantDigits, resolved.MaximumSignificantDigits = 1, 2
+	resolved.RoundingPriority = "morePrecision"
+	nf.rounding = roundingMorePrecision
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat/resolvedOptions
+func (nf *NumberFormat) ResolvedOptions() NumberFormatResolvedOptions {
+	return nf.options
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat/format
+func (nf *NumberFormat) Format(v float64) string {
+	var sb strings.Builder
+	for _, part := range nf.FormatToParts(v) {
+		sb.WriteString(part.Value)
+	}
+	return sb.String()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/NumberFormat/formatToParts
+func (nf *NumberFormat) FormatToParts(v float64) []Part {
+	var number []Part
+	var ops pluralOperands
+	isZero := false
+	switch {
+	case math.IsNaN(v):
+		number = []Part{{"nan", nf.symbols.NaN}}
+	case math.IsInf(v, 0):
+		number = []Part{{"infinity", nf.symbols.Infinity}}
+		ops.n = math.Inf(1)
+	default:
+		d := newDecimal(math.Abs(v))
+		if nf.options.Style == "percent" {
+			d = d.shift(2)
+		}
+		number, ops, isZero = nf.formatNumber(d)
+	}
+
+	negative := math.Signbit(v) && !math.IsNaN(v)
+	sign := 0
+	switch nf.options.SignDisplay {
+	case "auto":
+		if negative {
+			sign = -1
+		}
+	case "always":
+		sign = 1
+		if negative {
+			sign = -1
+		}
+	case "exceptZero":
+		if !isZero {
+			sign = 1
+			if negative {
+				sign = -1
+			}
+		}
+	case "negative":
+		if negative && !isZero {
+			sign = -1
+		}
+	}
+
+	prefix, suffix := nf.pattern.affixes(sign)
+	parts := nf.render(prefix, number, suffix)
+	switch {
+	case nf.options.Style == "unit":
+		forms := nf.data.Numbers.Units[nf.options.Unit][nf.options.UnitDisplay]
+		parts = applyUnitPattern(pluralForm(forms, nf.data.cardinal.selectCategory(ops)), parts, "unit", "")
+	case nf.options.Style == "currency" && nf.options.CurrencyDisplay == "name":
+		category := nf.data.cardinal.selectCategory(ops)
+		name := pluralForm(nf.currency.Names, category)
+		if name == "" {
+			name = nf.options.Currency
+		}
+		parts = applyUnitPattern(pluralForm(nf.data.Numbers.CurrencyUnitPattern, category), parts, "currency", name)
+	}
+	return parts
+}
+
+// operands returns the plural operands of v as nf formats it
+func (nf *NumberFormat) operands(v float64) pluralOperands {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return pluralOperands{n: math.Abs(v)}
+	}
+	_, ops, _ := nf.formatNumber(newDecimal(math.Abs(v)))
+	return ops
+}
+
+// formatNumber renders the digits of d for the notation, it also returns the
+// plural operands of the rounded value and whether it rounded to zero
+func (nf *NumberFormat) formatNumber(d decimal) ([]Part, pluralOperands, bool) {
+	switch nf.options.Notation {
+	case "scientific", "engineering":
+		step := 1
+		if nf.options.Notation == "engineering" {
+			step = 3
+		}
+		exponent := floorDiv(d.magnitude(), step) * step
+		rounded, minFraction := nf.round(d.shift(-exponent))
+		if !rounded.isZero() && rounded.magnitude() >= step {
+			exponent += step
+			rounded, minFraction = nf.round(d.shift(-exponent))
+		}
+		if rounded.isZero() {
+			exponent = 0
+		}
+		parts, ops := nf.digitParts(rounded, minFraction, false)
+		parts = append(parts, Part{"exponentSeparator", nf.symbols.Exponential})
+		if exponent < 0 {
+			parts = append(parts, Part{"exponentMinusSign", nf.symbols.MinusSign})
+		}
+		parts = append(parts, Part{"exponentInteger", nf.localizeDigits(strconv.Itoa(abs(exponent)))})
+		return parts, ops, rounded.isZero()
+	case "compact":
+		magnitude := d.magnitude()
+		for attempt := 0; attempt < 2; attempt++ {
+			exponent, forms := nf.compactPattern(magnitude)
+			rounded, minFraction := nf.round(d.shift(-exponent))
+			if !rounded.isZero() && rounded.magnitude()+exponent > magnitude && attempt == 0 {
+				// 999999 rounds up to the next pattern, "1M" instead of "1000K",
+				// and 999.9 to the first one, "1K" instead of "1000"
+				magnitude = rounded.magnitude() + exponent
+				continue
+			}
+			parts, ops := nf.digitParts(rounded, minFraction, true)
+			if forms == nil {
+				return parts, ops, rounded.isZero()
+			}
+			ops.e = float64(exponent)
+			pattern := pluralForm(forms, nf.data.cardinal.selectCategory(ops))
+			return applyUnitPattern(strings.Replace(pattern, "0", "{0}", 1), parts, "compact", ""), ops, rounded.isZero()
+		}
+	}
+	rounded, minFraction := nf.round(d)
+	parts, ops := nf.digitParts(rounded, minFraction, true)
+	return parts, ops, rounded.isZero()
+}
+
+// compactPattern finds the pattern for a magnitude, nil means the number is
+// too small to be compacted
+func (nf *NumberFormat) compactPattern(magnitude int) (int, map[string]string) {
+	patterns := nf.data.Numbers.Compact[nf.options.CompactDisplay]
+	exponents := make([]int, 0, len(patterns))
+	for key := range patterns {
+		exponent, _ := strconv.Atoi(key)
+		exponents = append(exponents, exponent)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(exponents)))
+	for _, exponent := range exponents {
+		if exponent > magnitude {
+			continue
+		}
+		forms := patterns[strconv.Itoa(exponent)]
+		if pluralForm(forms, "other") == "0" {
+			return 0, nil
+		}
+		return exponent, forms
+	}
+	return 0, nil
+}
+
+// round applies the rounding options, it also returns the minimum number of
+// fraction digits to show
+func (nf *NumberFormat) round(d decimal) (decimal, int) {
+	opts := nf.options
+	sigPosition := d.exp - opts.MaximumSignificantDigits
+	fractionPosition := -opts.MaximumFractionDigits
+	useSignificant := false
+	switch nf.rounding {
+	case roundingSignificantDigits:
+		useSignificant = true
+	case roundingMorePrecision:
+		useSignificant = sigPosition < fractionPosition
+	}
+	if !useSignificant {
+		return d.round(fractionPosition), opts.MinimumFractionDigits
+	}
+	rounded := d.round(sigPosition)
+	if rounded.isZero() {
+		return rounded, opts.MinimumSignificantDigits - 1
+	}
+	return rounded, max(opts.MinimumSignificantDigits-rounded.exp, 0)
+}
+
+// digitParts renders integer, group, decimal and fraction parts
+func (nf *NumberFormat) digitParts(d decimal, minFraction int, grouping bool) ([]Part, pluralOperands) {
+	integer, fraction := d.strings(nf.options.MinimumIntegerDigits, minFraction)
+	ops := newPluralOperands(integer, fraction, 0)
+	var parts []Part
+	primary, secondary := nf.decimal.primaryGroup, nf.decimal.secondaryGroup
+	if grouping && nf.minGrouping > 0 && primary > 0 && len(integer) >= primary+nf.minGrouping {
+		var groups []string
+		rest := integer
+		size := primary
+		for len(rest) > size {
+			groups = append([]string{rest[len(rest)-size:]}, groups...)
+			rest = rest[:len(rest)-size]
+			size = secondary
+		}
+		groups = append([]string{rest}, groups...)
+		for idx, group := range groups {
+			if idx > 0 {
+				parts = append(parts, Part{"group", nf.symbols.Group})
+			}
+			parts = append(parts, Part{"integer", nf.localizeDigits(group)})
+		}
+	} else {
+		parts = append(parts, Part{"integer", nf.localizeDigits(integer)})
+	}
+	if fraction != "" {
+		parts = append(parts, Part{"decimal", nf.symbols.Decimal}, Part{"fraction", nf.localizeDigits(fraction)})
+	}
+	return parts, ops
+}
+
+func (nf *NumberFormat) localizeDigits(ascii string) string {
+	if nf.options.NumberingSystem == "latn" {
+		return ascii
+	}
+	var sb strings.Builder
+	for _, c := range ascii {
+		sb.WriteString(nf.digits[c-'0'])
+	}
+	return sb.String()
+}
+
+// render resolves the affix tokens around the number parts
+func (nf *NumberFormat) render(prefix []affixToken, number []Part, suffix []affixToken) []Part {
+	currency := ""
+	if nf.options.Style == "currency" {
+		switch nf.options.CurrencyDisplay {
+		case "narrowSymbol":
+			currency = nf.currency.Narrow
+		case "code":
+			currency = nf.options.Currency
+		default:
+			currency = nf.currency.Symbol
+		}
+	}
+	var parts []Part
+	emit := func(tokens []affixToken) {
+		for _, token := range tokens {
+			switch token.kind {
+			case "literal":
+				parts = append(parts, Part{"literal", token.value})
+			case "currency":
+				parts = append(parts, Part{"currency", currency})
+			case "percentSign":
+				parts = append(parts, Part{"percentSign", nf.symbols.PercentSign})
+			case "minusSign":
+				parts = append(parts, Part{"minusSign", nf.symbols.MinusSign})
+			case "plusSign":
+				parts = append(parts, Part{"plusSign", nf.symbols.PlusSign})
+			}
+		}
+	}
+	emit(prefix)
+	// currency spacing, "USD 1.00" but "$1.00"
+	// https://unicode.org/reports/tr35/tr35-numbers.html#Currencies
+	if len(prefix) > 0 && prefix[len(prefix)-1].kind == "currency" {
+		if r, _ := utf8.DecodeLastRuneInString(currency); needsCurrencySpacing(r) {
+			parts = append(parts, Part{"literal", "\u00a0"})
+		}
+	}
+	parts = append(parts, number...)
+	if len(suffix) > 0 && suffix[0].kind == "currency" {
+		if r, _ := utf8.DecodeRuneInString(currency); needsCurrencySpacing(r) {
+			parts = append(parts, Part{"literal", "\u00a0"})
+		}
+	}
+	emit(suffix)
+	return parts
+}
+
+func needsCurrencySpacing(r rune) bool {
+	return r != utf8.RuneError && !unicode.IsSymbol(r) && !unicode.IsSpace(r)
+}
+
+// applyUnitPattern substitutes the parts for "{0}" in a unit, currency name or
+// compact pattern, the text around it becomes parts of the given type with
+// white space split off as literals, "{1}" is replaced by value
+func applyUnitPattern(pattern string, number []Part, partType string, value string) []Part {
+	var parts []Part
+	text := func(s string) {
+		for s != "" {
+			idx := strings.IndexFunc(s, unicode.IsSpace)
+			if idx == 0 {
+				end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+				if end < 0 {
+					end = len(s)
+				}
+				parts = append(parts, Part{"literal", s[:end]})
+				s = s[end:]
+				continue
+			}
+			if idx < 0 {
+				idx = len(s)
+			}
+			parts = append(parts, Part{partType, s[:idx]})
+			s = s[idx:]
+		}
+	}
+	for pattern != "" {
+		idx := strings.IndexByte(pattern, '{')
+		if idx < 0 || idx+2 >= len(pattern) || pattern[idx+2] != '}' {
+			text(pattern)
+			break
+		}
+		text(pattern[:idx])
+		switch pattern[idx+1] {
+		case '0':
+			parts = append(parts, number...)
+		case '1':
+			parts = append(parts, Part{partType, value})
+		}
+		pattern = pattern[idx+3:]
+	}
+	return parts
+}
+
+// pluralForm picks the form for a category, falling back to "other"
+func pluralForm(forms map[string]string, category string) string {
+	if form, ok := forms[category]; ok {
+		return form
+	}
+	return forms["other"]
+}
+
+// numberPattern is a parsed CLDR number pattern such as "¤#,##0.00;(¤#,##0.00)"
+//
+// https://unicode.org/reports/tr35/tr35-numbers.html#Number_Format_Patterns
+type numberPattern struct {
+	positivePrefix []affixToken
+	positiveSuffix []affixToken
+	negativePrefix []affixToken
+	negativeSuffix []affixToken
+	hasNegative    bool
+	primaryGroup   int
+	secondaryGroup int
+}
+
+type affixToken struct {
+	kind  string
+	value string
+}
+
+func parseNumberPattern(pattern string) numberPattern {
+	var p numberPattern
+	positive, negative, hasNegative := strings.Cut(pattern, ";")
+	var numeric string
+	p.positivePrefix, numeric, p.positiveSuffix = splitSubpattern(positive)
+	if hasNegative {
+		p.hasNegative = true
+		p.negativePrefix, _, p.negativeSuffix = splitSubpattern(negative)
+	}
+	integer, _, _ := strings.Cut(numeric, ".")
+	if last := strings.LastIndexByte(integer, ','); last >= 0 {
+		p.primaryGroup = len(integer) - last - 1
+		p.secondaryGroup = p.primaryGroup
+		if previous := strings.LastIndexByte(integer[:last], ','); previous >= 0 {
+			p.secondaryGroup = last - previous - 1
+		}
+	}
+	return p
+}
+
+func splitSubpattern(pattern string) ([]affixToken, string, []affixToken) {
+	first := strings.IndexAny(pattern, "#0,.@")
+	if first < 0 {
+		return tokenizeAffix(pattern), "", nil
+	}
+	last := strings.LastIndexAny(pattern, "#0,.@E")
+	return tokenizeAffix(pattern[:first]), pattern[first : last+1], tokenizeAffix(pattern[last+1:])
+}
+
+func tokenizeAffix(affix string) []affixToken {
+	var tokens []affixToken
+	literal := func(s string) {
+		if n := len(tokens); n > 0 && tokens[n-1].kind == "literal" {
+			tokens[n-1].value += s
+			return
+		}
+		tokens = append(tokens, affixToken{"literal", s})
+	}
+	quoted := false
+	for _, r := range affix {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+			literal(string(r))
+		case r == '¤':
+			tokens = append(tokens, affixToken{kind: "currency"})
+		case r == '%':
+			tokens = append(tokens, affixToken{kind: "percentSign"})
+		case r == '-':
+			tokens = append(tokens, affixToken{kind: "minusSign"})
+		case r == '+':
+			tokens = append(tokens, affixToken{kind: "plusSign"})
+		default:
+			literal(string(r))
+		}
+	}
+	return tokens
+}
+
+// affixes returns the prefix and suffix for a sign, -1 for minus, 1 for plus
+// and 0 for none, an implicit negative pattern is "-" before the positive one
+func (p numberPattern) affixes(sign int) ([]affixToken, []affixToken) {
+	if sign == 0 {
+		return p.positivePrefix, p.positiveSuffix
+	}
+	prefix := append([]affixToken{{kind: "minusSign"}}, p.positivePrefix...)
+	suffix := p.positiveSuffix
+	if p.hasNegative {
+		prefix, suffix = p.negativePrefix, p.negativeSuffix
+	}
+	if sign < 0 {
+		return prefix, suffix
+	}
+	// the plus pattern is the negative one with "+" in place of "-"
+	replaced := false
+	replace := func(tokens []affixToken) []affixToken {
+		result := make([]affixToken, len(tokens))
+		for idx, token := range tokens {
+			if token.kind == "minusSign" {
+				token.kind = "plusSign"
+				replaced = true
+			}
+			result[idx] = token
+		}
+		return result
+	}
+	prefix, suffix = replace(prefix), replace(suffix)
+	if !replaced {
+		return append([]affixToken{{kind: "plusSign"}}, p.positivePrefix...), p.positiveSuffix
+	}
+	return prefix, suffix
+}
+
+func oneOf(name, value string, allowed ...string) (string, error) {
+	if value == "" {
+		return allowed[0], nil
+	}
+	for _, candidate := range allowed[1:] {
+		if value == candidate {
+			return value, nil
+		}
+	}
+	if value == allowed[0] {
+		return value, nil
+	}
+	return "", fmt.Errorf("jsintl: invalid %s option %q", name, value)
+}
+
+func digitOption(name string, value *int, minimum, maximum, fallback int) (int, error) {
+	if value == nil {
+		return fallback, nil
+	}
+	if *value < minimum || *value > maximum {
+		return 0, fmt.Errorf("jsintl: %s value is out of range", name)
+	}
+	return *value, nil
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
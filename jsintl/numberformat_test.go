@@ -0,0 +1,95 @@
+package jsintl
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	cases := []struct {
+		locale  string
+		options NumberFormatOptions
+		value   float64
+		expect  string
+	}{
+		{"en", NumberFormatOptions{}, 1234567.891, "1,234,567.891"},
+		{"en", NumberFormatOptions{}, 1.005, "1.005"},
+		{"en", NumberFormatOptions{MaximumFractionDigits: Int(2)}, 1.005, "1.01"},
+		{"en", NumberFormatOptions{}, math.Copysign(0, -1), "-0"},
+		{"de-DE", NumberFormatOptions{}, 1234567.891, "1.234.567,891"},
+		{"fr", NumberFormatOptions{}, 1234567.891, "1\u202f234\u202f567,891"},
+		{"ar-EG", NumberFormatOptions{}, 1234.5, "١٬٢٣٤٫٥"},
+		{"en", NumberFormatOptions{Style: "currency", Currency: "USD"}, -1234.5, "-$1,234.50"},
+		{"en", NumberFormatOptions{Style: "currency", Currency: "USD", CurrencySign: "accounting"}, -1234.5, "($1,234.50)"},
+		{"en", NumberFormatOptions{Style: "currency", Currency: "USD", CurrencyDisplay: "code"}, 1234.5, "USD\u00a01,234.50"},
+		{"en", NumberFormatOptions{Style: "currency", Currency: "USD", CurrencyDisplay: "name"}, 1234.5, "1,234.50 US dollars"},
+		{"en", NumberFormatOptions{Style: "currency", Currency: "JPY"}, 1234.5, "¥1,235"},
+		{"de", NumberFormatOptions{Style: "currency", Currency: "EUR"}, -1234.5, "-1.234,50\u00a0€"},
+		{"zh", NumberFormatOptions{Style: "currency", Currency: "CNY"}, 1234.5, "¥1,234.50"},
+		{"en", NumberFormatOptions{Style: "percent"}, 0.256, "26%"},
+		{"de", NumberFormatOptions{Style: "percent"}, 0.256, "26\u00a0%"},
+		{"en", NumberFormatOptions{Notation: "compact"}, 1234, "1.2K"},
+		{"en", NumberFormatOptions{Notation: "compact"}, 123456, "123K"},
+		{"en", NumberFormatOptions{Notation: "compact"}, 999999, "1M"},
+		{"en", NumberFormatOptions{Notation: "compact"}, 999.9, "1K"},
+		{"en", NumberFormatOptions{Notation: "compact"}, -999.9, "-1K"},
+		{"en", NumberFormatOptions{Notation: "compact"}, 999.4, "999"},
+		{"en", NumberFormatOptions{Notation: "compact"}, 9.99, "10"},
+		{"en", NumberFormatOptions{Notation: "compact", CompactDisplay: "long"}, 1234567, "1.2 million"},
+		{"de", NumberFormatOptions{Notation: "compact"}, 1234, "1234"},
+		{"de", NumberFormatOptions{Notation: "compact"}, 1234567, "1,2\u00a0Mio."},
+		{"ja", NumberFormatOptions{Notation: "compact"}, 123456789, "1.2億"},
+		{"en", NumberFormatOptions{Style: "currency", Currency: "USD", Notation: "compact"}, 1234567, "$1.2M"},
+		{"en", NumberFormatOptions{Notation: "scientific"}, 123456, "1.235E5"},
+		{"en", NumberFormatOptions{Notation: "engineering"}, 0.000123456, "123.456E-6"},
+		{"en", NumberFormatOptions{MaximumSignificantDigits: Int(3)}, 123456, "123,000"},
+		{"en", NumberFormatOptions{MinimumFractionDigits: Int(2)}, 5, "5.00"},
+		{"en", NumberFormatOptions{Style: "unit", Unit: "kilometer-per-hour", UnitDisplay: "long"}, 50, "50 kilometers per hour"},
+		{"en", NumberFormatOptions{Style: "unit", Unit: "kilometer", UnitDisplay: "long"}, 1, "1 kilometer"},
+		{"en", NumberFormatOptions{SignDisplay: "always"}, 5, "+5"},
+		{"en", NumberFormatOptions{SignDisplay: "exceptZero"}, 0, "0"},
+		{"en", NumberFormatOptions{UseGrouping: "false"}, 12345, "12345"},
+		{"en", NumberFormatOptions{UseGrouping: "min2"}, 1234, "1234"},
+		{"en", NumberFormatOptions{}, math.Inf(-1), "-∞"},
+	}
+	for _, c := range cases {
+		nf, err := NewNumberFormat(c.locale, c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := nf.Format(c.value); result != c.expect {
+			t.Errorf("%s %+v: expect %q, got %q", c.locale, c.options, c.expect, result)
+		}
+	}
+}
+
+func TestNumberFormatToParts(t *testing.T) {
+	nf, _ := NewNumberFormat("de", NumberFormatOptions{Notation: "compact"})
+	parts := nf.FormatToParts(-1234567)
+	expect := []Part{{"minusSign", "-"}, {"integer", "1"}, {"decimal", ","}, {"fraction", "2"}, {"literal", "\u00a0"}, {"compact", "Mio."}}
+	if len(parts) != len(expect) {
+		t.Fatal(parts)
+	}
+	for idx := range parts {
+		if parts[idx] != expect[idx] {
+			t.Fatal(parts)
+		}
+	}
+}
+
+func TestNumberFormatOptions(t *testing.T) {
+	if _, err := NewNumberFormat("en", NumberFormatOptions{Style: "currency"}); err == nil {
+		t.FailNow()
+	}
+	if _, err := NewNumberFormat("en", NumberFormatOptions{MinimumFractionDigits: Int(3), MaximumFractionDigits: Int(1)}); err == nil {
+		t.FailNow()
+	}
+	nf, _ := NewNumberFormat("xx", NumberFormatOptions{Style: "currency", Currency: "eur"})
+	options := nf.ResolvedOptions()
+	if options.Locale != "en" || options.Currency != "EUR" || options.MinimumFractionDigits != 2 {
+		t.Fatal(options)
+	}
+	if locales := SupportedLocalesOf("de-AT", "xx", "zh-Hans-CN"); len(locales) != 2 || locales[1] != "zh-Hans-CN" {
+		t.Fatal(locales)
+	}
+}
@@ -0,0 +1,200 @@
+package jsintl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// plural categories in the order CLDR lists them
+var pluralCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+// pluralOperands are the CLDR operands of a formatted number
+//
+// https://unicode.org/reports/tr35/tr35-numbers.html#Operands
+type pluralOperands struct {
+	n float64 // absolute value
+	i float64 // integer digits
+	v float64 // number of visible fraction digits, with trailing zeros
+	w float64 // number of visible fraction digits, without trailing zeros
+	f float64 // visible fraction digits, with trailing zeros
+	t float64 // visible fraction digits, without trailing zeros
+	e float64 // compact decimal exponent
+}
+
+// newPluralOperands reads the operands from ASCII integer and fraction digits
+func newPluralOperands(integer, fraction string, exponent int) pluralOperands {
+	ops := pluralOperands{e: float64(exponent)}
+	ops.i, _ = strconv.ParseFloat(integer, 64)
+	ops.n = ops.i
+	if fraction != "" {
+		ops.n, _ = strconv.ParseFloat(integer+"."+fraction, 64)
+		ops.v = float64(len(fraction))
+		ops.f, _ = strconv.ParseFloat(fraction, 64)
+		trimmed := strings.TrimRight(fraction, "0")
+		ops.w = float64(len(trimmed))
+		if trimmed != "" {
+			ops.t, _ = strconv.ParseFloat(trimmed, 64)
+		}
+	}
+	return ops
+}
+
+func (ops pluralOperands) get(name string) float64 {
+	switch name {
+	case "n":
+		return ops.n
+	case "i":
+		return ops.i
+	case "v":
+		return ops.v
+	case "w":
+		return ops.w
+	case "f":
+		return ops.f
+	case "t":
+		return ops.t
+	default: // "e" and its alias "c"
+		return ops.e
+	}
+}
+
+// pluralRuleSet maps categories to their conditions, "other" matches when
+// nothing else does
+type pluralRuleSet []pluralRule
+
+type pluralRule struct {
+	category  string
+	condition [][]pluralRelation // or of ands
+}
+
+type pluralRelation struct {
+	operand string
+	modulus float64
+	negate  bool
+	ranges  [][2]float64
+}
+
+func (rs pluralRuleSet) selectCategory(ops pluralOperands) string {
+	for _, rule := range rs {
+		if rule.matches(ops) {
+			return rule.category
+		}
+	}
+	return "other"
+}
+
+func (rs pluralRuleSet) categories() []string {
+	result := make([]string, 0, len(rs)+1)
+	for _, rule := range rs {
+		result = append(result, rule.category)
+	}
+	return append(result, "other")
+}
+
+func (rule pluralRule) matches(ops pluralOperands) bool {
+	for _, and := range rule.condition {
+		matched := true
+		for _, relation := range and {
+			if !relation.matches(ops) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (relation pluralRelation) matches(ops pluralOperands) bool {
+	value := ops.get(relation.operand)
+	if relation.modulus != 0 {
+		value = math.Mod(value, relation.modulus)
+	}
+	in := false
+	for _, r := range relation.ranges {
+		// a range only contains integers, "n = 1..3" does not match 1.5
+		if value >= r[0] && value <= r[1] && (r[0] == r[1] || value == math.Trunc(value)) {
+			in = true
+			break
+		}
+	}
+	return in != relation.negate
+}
+
+func parsePluralRuleSet(rules map[string]string) (pluralRuleSet, error) {
+	var result pluralRuleSet
+	for _, category := range pluralCategories {
+		source, ok := rules[category]
+		if !ok || category == "other" {
+			continue
+		}
+		condition, err := parsePluralCondition(source)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pluralRule{category: category, condition: condition})
+	}
+	return result, nil
+}
+
+// parsePluralCondition parses the CLDR plural rule syntax, e.g.
+// "n % 10 = 3..4,9 and n % 100 != 10..19 or n = 0"
+//
+// https://unicode.org/reports/tr35/tr35-numbers.html#Plural_rules_syntax
+func parsePluralCondition(source string) ([][]pluralRelation, error) {
+	var condition [][]pluralRelation
+	for _, andSource := range strings.Split(source, " or ") {
+		var and []pluralRelation
+		for _, relationSource := range strings.Split(andSource, " and ") {
+			relation, err := parsePluralRelation(relationSource)
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, relation)
+		}
+		condition = append(condition, and)
+	}
+	return condition, nil
+}
+
+func parsePluralRelation(source string) (pluralRelation, error) {
+	var relation pluralRelation
+	expr, list, found := strings.Cut(source, "!=")
+	if found {
+		relation.negate = true
+	} else if expr, list, found = strings.Cut(source, "="); !found {
+		return relation, fmt.Errorf("invalid relation %q", source)
+	}
+	operand, modulus, hasModulus := strings.Cut(expr, "%")
+	relation.operand = strings.TrimSpace(operand)
+	if !strings.Contains("nivwftec", relation.operand) || len(relation.operand) != 1 {
+		return relation, fmt.Errorf("invalid operand in %q", source)
+	}
+	if hasModulus {
+		value, err := strconv.ParseFloat(strings.TrimSpace(modulus), 64)
+		if err != nil || value == 0 {
+			return relation, fmt.Errorf("invalid modulus in %q", source)
+		}
+		relation.modulus = value
+	}
+	for _, item := range strings.Split(list, ",") {
+		low, high, isRange := strings.Cut(strings.TrimSpace(item), "..")
+		if !isRange {
+			high = low
+		}
+		from, err := strconv.ParseFloat(low, 64)
+		if err != nil {
+			return relation, fmt.Errorf("invalid value in %q", source)
+		}
+		to, err := strconv.ParseFloat(high, 64)
+		if err != nil {
+			return relation, fmt.Errorf("invalid value in %q", source)
+		}
+		relation.ranges = append(relation.ranges, [2]float64{from, to})
+	}
+	return relation, nil
+}
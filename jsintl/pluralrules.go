@@ -0,0 +1,86 @@
+package jsintl
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/PluralRules/PluralRules#options
+type PluralRulesOptions struct {
+	// "cardinal" (default) or "ordinal"
+	Type string
+
+	MinimumIntegerDigits     int
+	MinimumFractionDigits    *int
+	MaximumFractionDigits    *int
+	MinimumSignificantDigits *int
+	MaximumSignificantDigits *int
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/PluralRules/resolvedOptions
+type PluralRulesResolvedOptions struct {
+	Locale           string
+	Type             string
+	PluralCategories []string
+
+	MinimumIntegerDigits     int
+	MinimumFractionDigits    int
+	MaximumFractionDigits    int
+	MinimumSignificantDigits int
+	MaximumSignificantDigits int
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/PluralRules
+type PluralRules struct {
+	options PluralRulesResolvedOptions
+	rules   pluralRuleSet
+	// the operands depend on the digits a NumberFormat would show, 1 is
+	// "one" but 1.0 with minimumFractionDigits: 1 is "other" in English
+	nf *NumberFormat
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/PluralRules/PluralRules
+func NewPluralRules(tag string, options ...PluralRulesOptions) (*PluralRules, error) {
+	var opts PluralRulesOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	pluralType, err := oneOf("type", opts.Type, "cardinal", "ordinal")
+	if err != nil {
+		return nil, err
+	}
+	nf, err := NewNumberFormat(tag, NumberFormatOptions{
+		NumberingSystem:          "latn",
+		MinimumIntegerDigits:     opts.MinimumIntegerDigits,
+		MinimumFractionDigits:    opts.MinimumFractionDigits,
+		MaximumFractionDigits:    opts.MaximumFractionDigits,
+		MinimumSignificantDigits: opts.MinimumSignificantDigits,
+		MaximumSignificantDigits: opts.MaximumSignificantDigits,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pr := &PluralRules{rules: nf.data.cardinal, nf: nf}
+	if pluralType == "ordinal" {
+		pr.rules = nf.data.ordinal
+	}
+	resolved := nf.ResolvedOptions()
+	pr.options = PluralRulesResolvedOptions{
+		Locale:                   resolved.Locale,
+		Type:                     pluralType,
+		PluralCategories:         pr.rules.categories(),
+		MinimumIntegerDigits:     resolved.MinimumIntegerDigits,
+		MinimumFractionDigits:    resolved.MinimumFractionDigits,
+		MaximumFractionDigits:    resolved.MaximumFractionDigits,
+		MinimumSignificantDigits: resolved.MinimumSignificantDigits,
+		MaximumSignificantDigits: resolved.MaximumSignificantDigits,
+	}
+	return pr, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/PluralRules/resolvedOptions
+func (pr *PluralRules) ResolvedOptions() PluralRulesResolvedOptions {
+	result := pr.options
+	result.PluralCategories = append([]string(nil), pr.options.PluralCategories...)
+	return result
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/PluralRules/select
+func (pr *PluralRules) Select(n float64) string {
+	return pr.rules.selectCategory(pr.nf.operands(n))
+}
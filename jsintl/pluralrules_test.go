@@ -0,0 +1,43 @@
+package jsintl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPluralRules(t *testing.T) {
+	cases := []struct {
+		locale  string
+		options PluralRulesOptions
+		values  []float64
+		expect  []string
+	}{
+		{"en", PluralRulesOptions{}, []float64{0, 1, 2, 1.5}, []string{"other", "one", "other", "other"}},
+		{"en", PluralRulesOptions{MinimumFractionDigits: Int(1)}, []float64{1}, []string{"other"}},
+		{"en", PluralRulesOptions{Type: "ordinal"}, []float64{1, 2, 3, 4, 11, 21, 22, 103}, []string{"one", "two", "few", "other", "other", "one", "two", "few"}},
+		{"fr", PluralRulesOptions{}, []float64{0, 1.5, 2}, []string{"one", "one", "other"}},
+		{"ar", PluralRulesOptions{}, []float64{0, 1, 2, 5, 11, 100}, []string{"zero", "one", "two", "few", "many", "other"}},
+		{"ja", PluralRulesOptions{}, []float64{1}, []string{"other"}},
+	}
+	for _, c := range cases {
+		pr, err := NewPluralRules(c.locale, c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for idx, value := range c.values {
+			if result := pr.Select(value); result != c.expect[idx] {
+				t.Errorf("%s %+v %v: expect %q, got %q", c.locale, c.options, value, c.expect[idx], result)
+			}
+		}
+	}
+	pr, err := NewPluralRules("en", PluralRulesOptions{Type: "ordinal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if categories := pr.ResolvedOptions().PluralCategories; !reflect.DeepEqual(categories, []string{"one", "two", "few", "other"}) {
+		t.Fatalf("unexpected categories %v", categories)
+	}
+	if _, err := NewPluralRules("en", PluralRulesOptions{Type: "decimal"}); err == nil {
+		t.Fatal("expect an error for an invalid type")
+	}
+}
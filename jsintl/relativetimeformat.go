@@ -0,0 +1,147 @@
+package jsintl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type relativeTimeData struct {
+	Future map[string]string `json:"future"`
+	Past   map[string]string `json:"past"`
+	// "-1" => "yesterday", "0" => "today", ... for numeric: "auto"
+	Relative map[string]string `json:"relative"`
+}
+
+var relativeTimeUnits = []string{"year", "quarter", "month", "week", "day", "hour", "minute", "second"}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat/RelativeTimeFormat#options
+type RelativeTimeFormatOptions struct {
+	// "long" (default), "short" or "narrow"
+	Style string
+	// "always" (default) or "auto"
+	Numeric string
+	// "latn", "arab", ... defaults to the locale's numbering system
+	NumberingSystem string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat/resolvedOptions
+type RelativeTimeFormatResolvedOptions struct {
+	Locale          string
+	Style           string
+	Numeric         string
+	NumberingSystem string
+}
+
+// RelativeTimePart is a Part that also carries the unit of the number parts
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat/formatToParts
+type RelativeTimePart struct {
+	Type  string
+	Value string
+	// singular unit for the number parts, empty for literals
+	Unit string
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat
+//
+// The narrow style uses the short patterns.
+type RelativeTimeFormat struct {
+	options RelativeTimeFormatResolvedOptions
+	data    *localeData
+	nf      *NumberFormat
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat/RelativeTimeFormat
+func NewRelativeTimeFormat(tag string, options ...RelativeTimeFormatOptions) (*RelativeTimeFormat, error) {
+	var opts RelativeTimeFormatOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	nf, err := NewNumberFormat(tag, NumberFormatOptions{NumberingSystem: opts.NumberingSystem})
+	if err != nil {
+		return nil, err
+	}
+	rtf := &RelativeTimeFormat{data: nf.data, nf: nf}
+	resolved := &rtf.options
+	resolved.Locale = nf.options.Locale
+	resolved.NumberingSystem = nf.options.NumberingSystem
+	if resolved.Style, err = oneOf("style", opts.Style, "long", "short", "narrow"); err != nil {
+		return nil, err
+	}
+	if resolved.Numeric, err = oneOf("numeric", opts.Numeric, "always", "auto"); err != nil {
+		return nil, err
+	}
+	return rtf, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat/resolvedOptions
+func (rtf *RelativeTimeFormat) ResolvedOptions() RelativeTimeFormatResolvedOptions {
+	return rtf.options
+}
+
+// Format accepts the singular and plural units, "day" and "days" are the same
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat/format
+func (rtf *RelativeTimeFormat) Format(value float64, unit string) (string, error) {
+	parts, err := rtf.FormatToParts(value, unit)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, part := range parts {
+		sb.WriteString(part.Value)
+	}
+	return sb.String(), nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/RelativeTimeFormat/formatToParts
+func (rtf *RelativeTimeFormat) FormatToParts(value float64, unit string) ([]RelativeTimePart, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("jsintl: invalid relative time value %v", value)
+	}
+	singular, err := singularRelativeTimeUnit(unit)
+	if err != nil {
+		return nil, err
+	}
+	style := rtf.options.Style
+	if style == "narrow" {
+		style = "short"
+	}
+	data := rtf.data.RelativeTime[style][singular]
+	if rtf.options.Numeric == "auto" && value == math.Trunc(value) {
+		if text, ok := data.Relative[strconv.FormatFloat(value, 'f', 0, 64)]; ok {
+			return []RelativeTimePart{{Type: "literal", Value: text}}, nil
+		}
+	}
+	forms := data.Future
+	if math.Signbit(value) {
+		forms = data.Past
+	}
+	category := rtf.data.cardinal.selectCategory(rtf.nf.operands(value))
+	number := rtf.nf.FormatToParts(math.Abs(value))
+	var parts []RelativeTimePart
+	for _, part := range applyUnitPattern(pluralForm(forms, category), number, "literal", "") {
+		if part.Type == "literal" {
+			if n := len(parts); n > 0 && parts[n-1].Type == "literal" {
+				parts[n-1].Value += part.Value
+				continue
+			}
+			parts = append(parts, RelativeTimePart{Type: part.Type, Value: part.Value})
+			continue
+		}
+		parts = append(parts, RelativeTimePart{Type: part.Type, Value: part.Value, Unit: singular})
+	}
+	return parts, nil
+}
+
+func singularRelativeTimeUnit(unit string) (string, error) {
+	singular := strings.TrimSuffix(unit, "s")
+	for _, candidate := range relativeTimeUnits {
+		if singular == candidate {
+			return singular, nil
+		}
+	}
+	return "", fmt.Errorf("jsintl: invalid unit %q", unit)
+}
@@ -0,0 +1,73 @@
+package jsintl
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRelativeTimeFormat(t *testing.T) {
+	cases := []struct {
+		locale  string
+		options RelativeTimeFormatOptions
+		value   float64
+		unit    string
+		expect  string
+	}{
+		{"en", RelativeTimeFormatOptions{}, 3, "days", "in 3 days"},
+		{"en", RelativeTimeFormatOptions{}, -1, "day", "1 day ago"},
+		{"en", RelativeTimeFormatOptions{}, math.Copysign(0, -1), "second", "0 seconds ago"},
+		{"en", RelativeTimeFormatOptions{}, 1.5, "hour", "in 1.5 hours"},
+		{"en", RelativeTimeFormatOptions{Numeric: "auto"}, -1, "day", "yesterday"},
+		{"en", RelativeTimeFormatOptions{Numeric: "auto"}, 0, "second", "now"},
+		{"en", RelativeTimeFormatOptions{Numeric: "auto"}, 2, "day", "in 2 days"},
+		{"en", RelativeTimeFormatOptions{Style: "short"}, -2, "quarter", "2 qtrs. ago"},
+		{"de", RelativeTimeFormatOptions{Numeric: "auto"}, 2, "day", "übermorgen"},
+		{"de", RelativeTimeFormatOptions{}, -3, "year", "vor 3 Jahren"},
+		{"fr", RelativeTimeFormatOptions{}, 2, "hours", "dans 2 heures"},
+		{"ja", RelativeTimeFormatOptions{}, -5, "minute", "5 分前"},
+		{"zh", RelativeTimeFormatOptions{}, 3, "month", "3个月后"},
+		{"ar", RelativeTimeFormatOptions{}, -1, "day", "قبل يوم واحد"},
+		{"ar", RelativeTimeFormatOptions{}, -2, "day", "قبل يومين"},
+		{"ar", RelativeTimeFormatOptions{}, 2, "hour", "خلال ساعتين"},
+		{"ar", RelativeTimeFormatOptions{}, -3, "day", "قبل 3 أيام"},
+		{"ar", RelativeTimeFormatOptions{}, 11, "month", "خلال 11 شهرًا"},
+		{"fr", RelativeTimeFormatOptions{Style: "short"}, 1, "day", "dans 1 j"},
+	}
+	for _, c := range cases {
+		rtf, err := NewRelativeTimeFormat(c.locale, c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := rtf.Format(c.value, c.unit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != c.expect {
+			t.Errorf("%s %+v %v %s: expect %q, got %q", c.locale, c.options, c.value, c.unit, c.expect, result)
+		}
+	}
+}
+
+func TestRelativeTimeFormatToParts(t *testing.T) {
+	rtf, err := NewRelativeTimeFormat("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts, err := rtf.FormatToParts(1000, "days")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []RelativeTimePart{
+		{"literal", "in ", ""}, {"integer", "1", "day"}, {"group", ",", "day"}, {"integer", "000", "day"}, {"literal", " days", ""},
+	}
+	if !reflect.DeepEqual(parts, expect) {
+		t.Fatalf("expect %v, got %v", expect, parts)
+	}
+	if _, err := rtf.Format(1, "fortnight"); err == nil {
+		t.Fatal("expect an error for an invalid unit")
+	}
+	if _, err := rtf.Format(math.NaN(), "day"); err == nil {
+		t.Fatal("expect an error for NaN")
+	}
+}
@@ -0,0 +1,140 @@
+package jspromise
+
+import (
+	"context"
+	"time"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortController
+type AbortController struct {
+	signal *AbortSignal
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortController/AbortController
+func NewAbortController() *AbortController {
+	return &AbortController{signal: newAbortSignal(context.Background())}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortController/signal
+func (c *AbortController) Signal() *AbortSignal {
+	return c.signal
+}
+
+// Abort aborts the signal with reason, ErrAbort by default, only the first
+// call has an effect
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortController/abort
+func (c *AbortController) Abort(reason ...error) {
+	c.signal.abort(reason...)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal
+//
+// A signal is a context.Context underneath, Context hands it to code that
+// takes a context and SignalFromContext goes the other way.
+type AbortSignal struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+func newAbortSignal(parent context.Context) *AbortSignal {
+	ctx, cancel := context.WithCancelCause(parent)
+	return &AbortSignal{ctx: ctx, cancel: cancel}
+}
+
+func (s *AbortSignal) abort(reason ...error) {
+	cause := ErrAbort
+	if len(reason) == 1 && reason[0] != nil {
+		cause = reason[0]
+	}
+	s.cancel(cause)
+}
+
+// SignalFromContext returns a signal that aborts when ctx ends, with
+// context.Cause(ctx) as its reason
+func SignalFromContext(ctx context.Context) *AbortSignal {
+	return newAbortSignal(ctx)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal/abort_static
+func AbortSignalAbort(reason ...error) *AbortSignal {
+	signal := newAbortSignal(context.Background())
+	signal.abort(reason...)
+	return signal
+}
+
+// AbortSignalTimeout aborts with ErrTimeout after d
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal/timeout_static
+func AbortSignalTimeout(d time.Duration) *AbortSignal {
+	signal := newAbortSignal(context.Background())
+	time.AfterFunc(d, func() {
+		signal.cancel(ErrTimeout)
+	})
+	return signal
+}
+
+// AbortSignalAny aborts with the reason of the first of signals to abort
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal/any_static
+func AbortSignalAny(signals ...*AbortSignal) *AbortSignal {
+	result := newAbortSignal(context.Background())
+	for _, signal := range signals {
+		if signal.Aborted() {
+			result.cancel(signal.Reason())
+			return result
+		}
+	}
+	stops := make([]func() bool, 0, len(signals))
+	for _, signal := range signals {
+		signal := signal
+		stops = append(stops, context.AfterFunc(signal.ctx, func() {
+			result.cancel(signal.Reason())
+		}))
+	}
+	// the sources may live much longer, don't keep result registered on them
+	context.AfterFunc(result.ctx, func() {
+		for _, stop := range stops {
+			stop()
+		}
+	})
+	return result
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal/aborted
+func (s *AbortSignal) Aborted() bool {
+	return s.ctx.Err() != nil
+}
+
+// Reason is nil until the signal aborts
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal/reason
+func (s *AbortSignal) Reason() error {
+	return context.Cause(s.ctx)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal/throwIfAborted
+func (s *AbortSignal) ThrowIfAborted() error {
+	return s.Reason()
+}
+
+// Done is closed when the signal aborts
+func (s *AbortSignal) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// OnAbort calls fn with the reason once the signal aborts, like the "abort"
+// event, stop unregisters it
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/API/AbortSignal/abort_event
+func (s *AbortSignal) OnAbort(fn func(reason error)) (stop func() bool) {
+	return context.AfterFunc(s.ctx, func() {
+		fn(s.Reason())
+	})
+}
+
+// Context returns a context that is canceled with the reason when the signal
+// aborts
+func (s *AbortSignal) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,48 @@
+package jspromise
+
+import (
+	"errors"
+
+	"d1y.io/jslike/result"
+)
+
+// ErrChanClosed is the rejection of FromChan when the channel is closed
+// before it delivers a value
+var ErrChanClosed = errors.New("channel closed without a value")
+
+// FromChan returns a Promise of the first value received from ch, it rejects
+// with ErrChanClosed when ch is closed first. One goroutine waits on ch until
+// then.
+func FromChan[T any](ch <-chan T) *Promise[T] {
+	p := newPromise[T]()
+	go func() {
+		value, ok := <-ch
+		if !ok {
+			var t T
+			p.settle(t, ErrChanClosed)
+			return
+		}
+		p.settle(value, nil)
+	}()
+	return p
+}
+
+// Chan returns a channel that receives the result once the Promise settles
+// and is closed after that
+func (p *Promise[T]) Chan() <-chan result.Result[T] {
+	// buffered so the send never blocks the settling goroutine
+	channel := make(chan result.Result[T], 1)
+	p.subscribe(func(value T, err error) {
+		channel <- result.New(value, err)
+		close(channel)
+	})
+	return channel
+}
+
+// Done returns a channel that is closed once the Promise settles, for select
+// statements. Await returns the result without blocking after that, Read
+// drops the error and leaves a rejection unhandled.
+func (p *Promise[T]) Done() <-chan struct{} {
+	p.start()
+	return p.done
+}
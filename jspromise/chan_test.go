@@ -0,0 +1,79 @@
+package jspromise
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan int)
+	p := FromChan(ch)
+	ch <- 1
+	if value, err := p.Await(); err != nil || value != 1 {
+		t.Fatal(value, err)
+	}
+
+	closed := make(chan int)
+	close(closed)
+	if _, err := FromChan(closed).Await(); err != ErrChanClosed {
+		t.Fatal(err)
+	}
+}
+
+func TestPromiseChan(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	p, _, reject := WithResolvers[int]()
+	channel := p.Chan()
+	select {
+	case <-p.Done():
+		t.Fatal("expect a pending promise")
+	case <-channel:
+		t.Fatal("expect a pending promise")
+	case <-time.After(time.Millisecond * 10):
+	}
+	reject(sentinel)
+	<-p.Done()
+	if r, ok := <-channel; !ok || r.Err() != sentinel {
+		t.Fatal(r, ok)
+	}
+	if _, ok := <-channel; ok {
+		t.Fatal("expect the channel to be closed")
+	}
+	if r := <-Resolve(2).Chan(); r.Unwrap() != 2 {
+		t.Fatal(r)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var group Group[int]
+	group.SetLimit(2)
+	for i := 0; i < 5; i++ {
+		value := i
+		group.Go(func(ctx context.Context) (int, error) {
+			return value * 2, nil
+		})
+	}
+	results, err := group.Wait().Await()
+	if err != nil || len(results) != 5 || results[4] != 8 {
+		t.Fatal(results, err)
+	}
+
+	sentinel := errors.New("sentinel")
+	group2, ctx := GroupWithContext[int](context.Background())
+	group2.Go(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	group2.Go(func(ctx context.Context) (int, error) {
+		return 0, sentinel
+	})
+	var allError *AllError
+	if _, err := group2.Wait().Await(); !errors.As(err, &allError) || allError.Index != 1 {
+		t.Fatal(err)
+	}
+	if context.Cause(ctx) != sentinel {
+		t.Fatal(context.Cause(ctx))
+	}
+}
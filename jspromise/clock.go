@@ -0,0 +1,101 @@
+package jspromise
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of Retry, a FakeClock makes schedules testable
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f once d passed, stop cancels the call and reports
+	// whether it did like time.Timer.Stop
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// RealClock is the Clock of the time package
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// FakeClock only moves when Advance is called, it runs the due functions on
+// the goroutine that calls Advance
+type FakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	when time.Time
+	f    func()
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	timer := &fakeTimer{when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return func() bool {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		for idx, t := range c.timers {
+			if t == timer {
+				c.timers = append(c.timers[:idx], c.timers[idx+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance moves the clock forward by d and runs the functions that are due by
+// then in the order of their time, including ones they schedule
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	end := c.now.Add(d)
+	for {
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			return c.timers[i].when.Before(c.timers[j].when)
+		})
+		if len(c.timers) == 0 || c.timers[0].when.After(end) {
+			break
+		}
+		timer := c.timers[0]
+		c.timers = c.timers[1:]
+		if timer.when.After(c.now) {
+			c.now = timer.when
+		}
+		c.mutex.Unlock()
+		timer.f()
+		c.mutex.Lock()
+	}
+	c.now = end
+	c.mutex.Unlock()
+}
+
+// Pending returns the number of functions waiting for their time
+func (c *FakeClock) Pending() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
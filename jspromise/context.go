@@ -0,0 +1,61 @@
+package jspromise
+
+import (
+	"context"
+	"time"
+)
+
+// Creates a new Promise that runs fn with ctx, the Promise rejects with
+// context.Cause(ctx) as soon as ctx ends even if fn is still running, fn should
+// watch ctx to stop its work early.
+func NewWithContext[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Promise[T] {
+	p := newPromise[T]()
+	stop := context.AfterFunc(ctx, func() {
+		var t T
+		p.settle(t, context.Cause(ctx))
+	})
+
+	p.executor.Execute(func() {
+		result, err := call(func() (T, error) { return fn(ctx) })
+		if !stop() {
+			// ctx ended first, fn probably returned because of it
+			var t T
+			p.settle(t, context.Cause(ctx))
+			return
+		}
+		p.settle(result, err)
+	})
+
+	return p
+}
+
+// Waits for this Promise like Await, but gives up with context.Cause(ctx) when
+// ctx ends first. The Promise itself keeps running.
+func (p *Promise[T]) AwaitContext(ctx context.Context) (T, error) {
+	p.start()
+	select {
+	case <-p.done:
+		p.observed.Store(true)
+		return p.value, p.err
+	case <-ctx.Done():
+		var t T
+		return t, context.Cause(ctx)
+	}
+}
+
+// Creates a new Promise that settles like p, or rejects with ErrTimeout when p
+// takes longer than d.
+func Timeout[T any](p *Promise[T], d time.Duration) *Promise[T] {
+	result := derive[T](p)
+	timer := time.AfterFunc(d, func() {
+		var t T
+		result.settle(t, ErrTimeout)
+	})
+
+	p.subscribe(func(value T, err error) {
+		timer.Stop()
+		result.settle(value, err)
+	})
+
+	return result
+}
@@ -0,0 +1,122 @@
+package jspromise
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	sentinel := errors.New("sentinel")
+	stopped := make(chan struct{})
+	p := NewWithContext(ctx, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(stopped)
+		return 1, nil
+	})
+	cancel(sentinel)
+	if _, err := p.Await(); err != sentinel {
+		t.Fatal("expect the cause of the context", err)
+	}
+	<-stopped
+	if p.State() != Rejected {
+		t.Fatal(p.State())
+	}
+
+	p = NewWithContext(context.Background(), func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	if value, err := p.Await(); err != nil || value != 2 {
+		t.Fatal(value, err)
+	}
+}
+
+func TestAwaitContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	p := New(func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	if _, err := p.AwaitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+	if !p.IsPending() {
+		t.Fatal("expect the promise to keep running")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	slow := New(func() (int, error) {
+		time.Sleep(time.Millisecond * 200)
+		return 1, nil
+	})
+	if _, err := Timeout(slow, time.Millisecond*20).Await(); err != ErrTimeout || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+	if value, err := Timeout(slow, time.Second).Await(); err != nil || value != 1 {
+		t.Fatal(value, err)
+	}
+}
+
+func TestAbortController(t *testing.T) {
+	controller := NewAbortController()
+	signal := controller.Signal()
+	if signal.Aborted() || signal.Reason() != nil || signal.ThrowIfAborted() != nil {
+		t.Fatal("expect a fresh signal")
+	}
+	reasons := make(chan error, 1)
+	signal.OnAbort(func(reason error) {
+		reasons <- reason
+	})
+	p := NewWithContext(signal.Context(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, nil
+	})
+	controller.Abort()
+	controller.Abort(errors.New("ignored"))
+	if <-reasons != ErrAbort || !errors.Is(signal.Reason(), context.Canceled) {
+		t.Fatal(signal.Reason())
+	}
+	if _, err := p.Await(); err != ErrAbort {
+		t.Fatal(err)
+	}
+	<-signal.Done()
+
+	sentinel := errors.New("sentinel")
+	if AbortSignalAbort(sentinel).Reason() != sentinel {
+		t.Fatal("expect an aborted signal")
+	}
+}
+
+func TestAbortSignalStatics(t *testing.T) {
+	timeout := AbortSignalTimeout(time.Millisecond * 20)
+	<-timeout.Done()
+	if timeout.Reason() != ErrTimeout {
+		t.Fatal(timeout.Reason())
+	}
+
+	first, second := NewAbortController(), NewAbortController()
+	anySignal := AbortSignalAny(first.Signal(), second.Signal())
+	sentinel := errors.New("sentinel")
+	second.Abort(sentinel)
+	<-anySignal.Done()
+	if anySignal.Reason() != sentinel {
+		t.Fatal(anySignal.Reason())
+	}
+	if AbortSignalAny(anySignal, first.Signal()).Reason() != sentinel {
+		t.Fatal("expect an already aborted source to abort at once")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fromContext := SignalFromContext(ctx)
+	cancel()
+	<-fromContext.Done()
+	if fromContext.Reason() != context.Canceled {
+		t.Fatal(fromContext.Reason())
+	}
+}
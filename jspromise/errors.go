@@ -0,0 +1,111 @@
+package jspromise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/AggregateError
+//
+// Unwrap exposes every error to errors.Is and errors.As.
+type AggregateError struct {
+	Errors []error
+}
+
+func (e *AggregateError) Error() string {
+	if len(e.Errors) == 0 {
+		return "all promises were rejected"
+	}
+	messages := make([]string, len(e.Errors))
+	for idx, err := range e.Errors {
+		messages[idx] = err.Error()
+	}
+	return fmt.Sprintf("all promises were rejected: %s", strings.Join(messages, "; "))
+}
+
+func (e *AggregateError) Unwrap() []error {
+	return e.Errors
+}
+
+// AllError is the rejection of All, All2 and All3, Index is the position of
+// the Promise that rejected first
+type AllError struct {
+	Index int
+	Err   error
+}
+
+func (e *AllError) Error() string {
+	return fmt.Sprintf("promise %d rejected: %v", e.Index, e.Err)
+}
+
+func (e *AllError) Unwrap() error {
+	return e.Err
+}
+
+// ErrAbort is the default reason of AbortController.Abort, it matches
+// context.Canceled with errors.Is
+var ErrAbort error = abortError{}
+
+// ErrTimeout is the rejection of Timeout and the reason of AbortSignalTimeout,
+// it matches context.DeadlineExceeded with errors.Is
+var ErrTimeout error = timeoutError{}
+
+type abortError struct{}
+
+func (abortError) Error() string {
+	return "the operation was aborted"
+}
+
+func (abortError) Is(target error) bool {
+	return target == context.Canceled
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string {
+	return "the operation timed out"
+}
+
+func (timeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// PanicError is the rejection of a Promise whose function or callback
+// panicked, Stack is the stack of the panicking goroutine
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value when it is an error, such as a runtime.Error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// ErrNilReason replaces a nil error passed to Reject or a reject function,
+// a nil error would otherwise mean the Promise fulfilled
+var ErrNilReason = errors.New("promise rejected with a nil error")
+
+// RetryError is the rejection of Retry once it ran out of attempts or time,
+// Err is the error of the last attempt
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,163 @@
+package jspromise
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// An Executor runs the executor functions of New and the callbacks of Then,
+// Catch and Finally, like the job queue of a JS engine. Execute must not block
+// until the task finished, a task may queue more tasks.
+type Executor interface {
+	Execute(task func())
+}
+
+// ExecutorFunc adapts a function to an Executor
+type ExecutorFunc func(task func())
+
+func (f ExecutorFunc) Execute(task func()) {
+	f(task)
+}
+
+var (
+	// Go starts a goroutine for every task, the default
+	Go Executor = ExecutorFunc(func(task func()) { go task() })
+	// Inline runs the task right away on the goroutine that settles the
+	// Promise, for cheap continuations. A slow callback delays everything
+	// waiting on that goroutine.
+	Inline Executor = ExecutorFunc(func(task func()) { task() })
+)
+
+var defaultExecutor atomic.Pointer[Executor]
+
+// SetDefaultExecutor changes the Executor of the Promises created from now
+// on, Promises created by Then and friends keep the Executor of the Promise
+// they continue. A nil Executor restores Go.
+func SetDefaultExecutor(executor Executor) {
+	if executor == nil {
+		defaultExecutor.Store(nil)
+		return
+	}
+	defaultExecutor.Store(&executor)
+}
+
+// DefaultExecutor returns the Executor set by SetDefaultExecutor
+func DefaultExecutor() Executor {
+	if executor := defaultExecutor.Load(); executor != nil {
+		return *executor
+	}
+	return Go
+}
+
+// Pool runs tasks on at most size goroutines, which are started when there is
+// work and stay until Close. Execute never blocks, tasks wait in an unbounded
+// queue, so a task can queue more work without deadlocking the Pool.
+type Pool struct {
+	mutex   sync.Mutex
+	cond    sync.Cond
+	tasks   []func()
+	size    int
+	workers int
+	idle    int
+	closed  bool
+}
+
+// NewPool returns a Pool of size goroutines, size less than 1 means 1
+func NewPool(size int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	p := &Pool{size: size}
+	p.cond.L = &p.mutex
+	return p
+}
+
+// Execute queues task, it panics when the Pool is closed
+func (p *Pool) Execute(task func()) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed {
+		panic("jspromise: Execute on a closed Pool")
+	}
+	p.tasks = append(p.tasks, task)
+	switch {
+	case p.idle > 0:
+		p.idle--
+		p.cond.Signal()
+	case p.workers < p.size:
+		p.workers++
+		go p.work()
+	}
+}
+
+func (p *Pool) work() {
+	for {
+		task, ok := p.next()
+		if !ok {
+			return
+		}
+		task()
+	}
+}
+
+func (p *Pool) next() (func(), bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for len(p.tasks) == 0 {
+		if p.closed {
+			p.workers--
+			return nil, false
+		}
+		p.idle++
+		p.cond.Wait()
+	}
+	task := p.tasks[0]
+	p.tasks[0] = nil
+	p.tasks = p.tasks[1:]
+	return task, true
+}
+
+// Close lets the goroutines exit once the queued tasks ran, it does not wait
+// for them
+func (p *Pool) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = true
+	p.cond.Broadcast()
+}
+
+// EventLoop runs tasks one at a time in the order they were queued, on the
+// goroutine that calls Run, like the event loop of a JS runtime. A task that
+// awaits a Promise settled by a later task blocks the loop forever, use Then
+// instead.
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Event_loop
+type EventLoop struct {
+	pool *Pool
+}
+
+func NewEventLoop() *EventLoop {
+	// a Pool that never starts a worker, Run is the only one
+	pool := &Pool{}
+	pool.cond.L = &pool.mutex
+	return &EventLoop{pool: pool}
+}
+
+// Execute queues task, it panics when the loop is stopped
+func (l *EventLoop) Execute(task func()) {
+	l.pool.Execute(task)
+}
+
+// Run runs the queued tasks and waits for more until Stop is called and the
+// queue is empty, only one goroutine may call it
+func (l *EventLoop) Run() {
+	l.pool.mutex.Lock()
+	l.pool.workers++
+	l.pool.mutex.Unlock()
+	l.pool.work()
+}
+
+// Stop makes Run return once the queued tasks ran
+func (l *EventLoop) Stop() {
+	l.pool.Close()
+}
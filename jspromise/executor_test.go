@@ -0,0 +1,139 @@
+package jspromise
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInlineExecutor(t *testing.T) {
+	p := NewWithExecutor(Inline, func() (int, error) { return 1, nil })
+	if p.State() != Fulfilled {
+		t.Fatal("expect fn to run synchronously")
+	}
+	if then := Then(p, func(value int) (int, error) { return value + 1, nil }); then.State() != Fulfilled {
+		t.Fatal("expect the continuation to run synchronously")
+	}
+
+	SetDefaultExecutor(Inline)
+	defer SetDefaultExecutor(nil)
+	if p := New(func() (int, error) { return 1, nil }); p.State() != Fulfilled {
+		t.Fatal("expect New to use the default executor")
+	}
+}
+
+func TestPool(t *testing.T) {
+	pool := NewPool(4)
+	defer pool.Close()
+
+	var running, peak atomic.Int32
+	promises := make([]*Promise[int], 100)
+	for i := range promises {
+		idx := i
+		promises[i] = NewWithExecutor(pool, func() (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				max := peak.Load()
+				if n <= max || peak.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			runtime.Gosched()
+			return idx, nil
+		})
+	}
+	results, err := All(promises)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, value := range results {
+		if idx != value {
+			t.Fatal(results)
+		}
+	}
+	if peak.Load() > 4 {
+		t.Fatal("expect at most 4 tasks at once, got", peak.Load())
+	}
+}
+
+func TestEventLoop(t *testing.T) {
+	loop := NewEventLoop()
+	var order []int
+	p := NewWithExecutor(loop, func() (int, error) {
+		order = append(order, 1)
+		return 1, nil
+	})
+	Then(p, func(value int) (int, error) {
+		order = append(order, 3)
+		loop.Stop()
+		return value, nil
+	})
+	loop.Execute(func() {
+		order = append(order, 2)
+	})
+	loop.Run()
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Fatal(order)
+	}
+}
+
+func TestContinuationGoroutines(t *testing.T) {
+	root, resolve, _ := WithResolvers[int]()
+	before := runtime.NumGoroutine()
+	promises := make([]*Promise[int], 1000)
+	for i := range promises {
+		promises[i] = Then(root, func(value int) (int, error) { return value, nil })
+	}
+	Timeout(root, time.Hour)
+	if after := runtime.NumGoroutine(); after-before > 10 {
+		t.Fatal("expect pending continuations not to start goroutines, got", after-before)
+	}
+	resolve(1)
+	if _, err := All(promises); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// benchmarkFanOut hangs n continuations on a pending Promise, the goroutines
+// metric is how many goroutines they hold while they wait
+func benchmarkFanOut(b *testing.B, executor Executor) {
+	const n = 10000
+	SetDefaultExecutor(executor)
+	defer SetDefaultExecutor(nil)
+	b.ReportAllocs()
+	var waiting int
+	for i := 0; i < b.N; i++ {
+		before := runtime.NumGoroutine()
+		root, resolve, _ := WithResolvers[int]()
+		promises := make([]*Promise[int], n)
+		for j := range promises {
+			promises[j] = Catch(Then(root, func(value int) (int, error) {
+				return value * 2, nil
+			}), func(err error) (int, error) {
+				return 0, nil
+			})
+		}
+		waiting += runtime.NumGoroutine() - before
+		resolve(1)
+		if _, err := All(promises); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(waiting)/float64(b.N), "goroutines/op")
+}
+
+func BenchmarkFanOutGo(b *testing.B) {
+	benchmarkFanOut(b, Go)
+}
+
+func BenchmarkFanOutPool(b *testing.B) {
+	pool := NewPool(runtime.GOMAXPROCS(0))
+	defer pool.Close()
+	benchmarkFanOut(b, pool)
+}
+
+func BenchmarkFanOutInline(b *testing.B) {
+	benchmarkFanOut(b, Inline)
+}
@@ -0,0 +1,107 @@
+package jspromise
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Group runs functions like golang.org/x/sync/errgroup, Wait returns a
+// Promise of all of their results. The zero Group runs without a context or a
+// limit.
+type Group[T any] struct {
+	mutex    sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	limiter  *Limiter
+	promises promiseList[T]
+}
+
+// GroupWithContext returns a Group and a context derived from ctx that is
+// canceled with the first error of the Group, or once Wait settles
+func GroupWithContext[T any](ctx context.Context) (*Group[T], context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group[T]{ctx: ctx, cancel: cancel}, ctx
+}
+
+// SetLimit runs at most n functions of the Group at once, the others wait in
+// a queue without holding a goroutine. It must be called before Go.
+func (g *Group[T]) SetLimit(n int) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.limiter = NewLimiter(n)
+}
+
+// Go runs fn on the DefaultExecutor and returns its Promise
+func (g *Group[T]) Go(fn func(ctx context.Context) (T, error)) *Promise[T] {
+	g.mutex.Lock()
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	limiter := g.limiter
+	g.mutex.Unlock()
+
+	var p *Promise[T]
+	run := func(ctx context.Context) *Promise[T] {
+		return New(func() (T, error) { return fn(ctx) })
+	}
+	if limiter != nil {
+		p = Limit(limiter, ctx, run)
+	} else {
+		p = run(ctx)
+	}
+	p.subscribe(func(_ T, err error) {
+		if err != nil && g.cancel != nil {
+			g.cancel(err)
+		}
+	})
+
+	g.mutex.Lock()
+	g.promises = append(g.promises, p)
+	g.mutex.Unlock()
+	return p
+}
+
+// Wait returns a Promise of the results of the functions started so far, in
+// the order of the calls to Go. It rejects with an *AllError for the first
+// error like All.
+func (g *Group[T]) Wait() *Promise[[]T] {
+	g.mutex.Lock()
+	promises := append(promiseList[T](nil), g.promises...)
+	g.mutex.Unlock()
+
+	p := allOf(promises)
+	if g.cancel != nil {
+		p.subscribe(func(_ []T, _ error) {
+			g.cancel(nil)
+		})
+	}
+	return p
+}
+
+// allOf is All as a Promise, without waiting on a goroutine
+func allOf[T any](promises promiseList[T]) *Promise[[]T] {
+	p := newPromise[[]T]()
+	results := make([]T, len(promises))
+	if len(promises) == 0 {
+		p.settle(results, nil)
+		return p
+	}
+	var remaining atomic.Int64
+	remaining.Store(int64(len(promises)))
+	for i, promise := range promises {
+		idx := i
+		promise.subscribe(func(value T, err error) {
+			if err != nil {
+				p.settle(nil, &AllError{Index: idx, Err: err})
+				return
+			}
+			results[idx] = value
+			if remaining.Add(-1) == 0 {
+				p.settle(results, nil)
+			}
+		})
+	}
+	return p
+}
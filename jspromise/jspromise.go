@@ -1,6 +1,10 @@
 package jspromise
 
-import "sync"
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
 
 // copy by https://github.com/ncpa0cpl/promise
 
@@ -9,73 +13,175 @@ type awaiterResult[T any] struct {
 	err   error
 }
 
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise#描述
+type State int32
+
+const (
+	Pending State = iota
+	Fulfilled
+	Rejected
+)
+
+func (s State) String() string {
+	switch s {
+	case Fulfilled:
+		return "fulfilled"
+	case Rejected:
+		return "rejected"
+	default:
+		return "pending"
+	}
+}
+
+// A Promise settles exactly once, value and err are written before done is
+// closed and never change afterwards, so anyone who saw done closed can read
+// them without the mutex.
 type Promise[T any] struct {
-	mutex      sync.Mutex
-	result     T
-	err        error
-	isFinished bool
-	awaiters   []chan awaiterResult[T]
+	mutex sync.Mutex
+	state State
+	value T
+	err   error
+	done  chan struct{}
+	// run once the Promise settles, instead of goroutines blocked in Await
+	callbacks []func(value T, err error)
+	// runs New and the callbacks of Then, Catch and Finally
+	executor Executor
+	// set once someone awaits the result, see OnUnhandledRejection
+	observed atomic.Bool
+	// starts the work of a Lazy Promise on first use
+	lazy atomic.Pointer[func()]
 }
 
-func (p *Promise[T]) finalize(result T, err error) {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
+func newPromise[T any]() *Promise[T] {
+	return &Promise[T]{done: make(chan struct{}), executor: DefaultExecutor()}
+}
 
-	p.result = result
-	p.err = err
-	p.isFinished = true
+// derive returns a pending Promise that continues p on the same Executor
+func derive[U any, T any](p *Promise[T]) *Promise[U] {
+	return &Promise[U]{done: make(chan struct{}), executor: p.executor}
+}
 
-	for _, channel := range p.awaiters {
-		channel <- awaiterResult[T]{value: result, err: err}
+// settle moves a pending Promise to fulfilled or rejected, later calls are
+// ignored like a second resolve in JS
+func (p *Promise[T]) settle(value T, err error) bool {
+	p.mutex.Lock()
+	if p.state != Pending {
+		p.mutex.Unlock()
+		return false
+	}
+	p.value = value
+	p.err = err
+	if err != nil {
+		p.state = Rejected
+		if unhandledRejectionHook.Load() != nil {
+			runtime.SetFinalizer(p, (*Promise[T]).reportUnhandled)
+		}
+	} else {
+		p.state = Fulfilled
 	}
+	callbacks := p.callbacks
+	p.callbacks = nil
+	close(p.done)
+	p.mutex.Unlock()
+	for _, callback := range callbacks {
+		callback(value, err)
+	}
+	return true
+}
 
-	p.awaiters = []chan awaiterResult[T]{}
+// subscribe calls fn with the result once p settles, right away when it
+// already has. fn runs on the settling goroutine and must be quick, hand real
+// work to the Executor.
+func (p *Promise[T]) subscribe(fn func(value T, err error)) {
+	p.observed.Store(true)
+	p.start()
+	p.onSettle(fn)
+}
+
+// onSettle is subscribe for bookkeeping that neither handles a rejection nor
+// starts a Lazy Promise
+func (p *Promise[T]) onSettle(fn func(value T, err error)) {
+	p.mutex.Lock()
+	if p.state == Pending {
+		p.callbacks = append(p.callbacks, fn)
+		p.mutex.Unlock()
+		return
+	}
+	p.mutex.Unlock()
+	fn(p.value, p.err)
 }
 
 // Creates a new Promise that will run once this Promise
 // goroutine finishes.
 func (p *Promise[T]) Finally(fn func(value T, err error) error) *Promise[T] {
-	return New(func() (T, error) {
-		r, parentError := p.Await()
-
-		finallyError := fn(r, parentError)
-		return r, finallyError
+	result := derive[T](p)
+	p.subscribe(func(value T, err error) {
+		p.executor.Execute(func() {
+			_, finallyError := callWith(func(err error) (struct{}, error) {
+				return struct{}{}, fn(value, err)
+			}, err)
+			result.settle(value, finallyError)
+		})
 	})
+	return result
 }
 
 // Waits for this Promise goroutine to finish and returns it's result.
 func (p *Promise[T]) Await() (T, error) {
-	if p.isFinished {
-		return p.result, p.err
-	}
-
-	p.mutex.Lock()
+	p.start()
+	<-p.done
+	p.observed.Store(true)
+	return p.value, p.err
+}
 
-	if p.isFinished {
-		p.mutex.Unlock()
-		return p.result, p.err
+// start runs the function of a Lazy Promise once
+func (p *Promise[T]) start() {
+	if start := p.lazy.Swap(nil); start != nil {
+		(*start)()
 	}
+}
 
-	channel := make(chan awaiterResult[T])
-
-	p.awaiters = append(p.awaiters, channel)
-
-	p.mutex.Unlock()
-
-	result := <-channel
-
-	return result.value, result.err
+// State reports whether the Promise is pending, fulfilled or rejected
+func (p *Promise[T]) State() State {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.state
 }
 
 func (p *Promise[T]) IsPending() bool {
-	return !p.isFinished
+	return p.State() == Pending
 }
 
 func (p *Promise[T]) Read() (T, bool) {
-	if p.isFinished {
-		return p.result, true
+	select {
+	case <-p.done:
+		return p.value, true
+	default:
+		var t T
+		return t, false
+	}
+}
+
+var unhandledRejectionHook atomic.Pointer[func(err error)]
+
+// OnUnhandledRejection sets a hook like process.on('unhandledRejection'), it
+// is called with the error of a rejected Promise that was garbage collected
+// without anyone awaiting it. Go has no microtask queue to tell when a
+// rejection is too late to handle, so the report waits for the collector. A
+// nil hook removes it.
+func OnUnhandledRejection(hook func(err error)) {
+	if hook == nil {
+		unhandledRejectionHook.Store(nil)
+		return
 	}
+	unhandledRejectionHook.Store(&hook)
+}
 
-	var t T
-	return t, false
+func (p *Promise[T]) reportUnhandled() {
+	if p.observed.Load() {
+		return
+	}
+	if hook := unhandledRejectionHook.Load(); hook != nil {
+		(*hook)(p.err)
+	}
 }
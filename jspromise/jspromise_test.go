@@ -1,6 +1,10 @@
 package jspromise
 
 import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -19,3 +23,295 @@ func TestPromise(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestPromiseState(t *testing.T) {
+	release := make(chan struct{})
+	fulfilled := New(func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	rejected := New(func() (int, error) {
+		<-release
+		return 0, errors.New("rejected")
+	})
+	if fulfilled.State() != Pending || !rejected.IsPending() {
+		t.Fatal("expect pending promises")
+	}
+	if _, ok := fulfilled.Read(); ok {
+		t.Fatal("expect no value before settling")
+	}
+	close(release)
+	fulfilled.Await()
+	rejected.Await()
+	if fulfilled.State() != Fulfilled || rejected.State() != Rejected {
+		t.Fatal(fulfilled.State(), rejected.State())
+	}
+	if rejected.State().String() != "rejected" {
+		t.Fatal(rejected.State().String())
+	}
+}
+
+// run with -race
+func TestPromiseConcurrent(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		release := make(chan struct{})
+		p := New(func() (int, error) {
+			<-release
+			return round, nil
+		})
+		var waitGroup sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			waitGroup.Add(4)
+			go func() {
+				defer waitGroup.Done()
+				if value, err := p.Await(); value != round || err != nil {
+					t.Error("unexpected result", value, err)
+				}
+			}()
+			go func() {
+				defer waitGroup.Done()
+				value, _ := Then(p, func(value int) (int, error) { return value * 2, nil }).Await()
+				if value != round*2 {
+					t.Error("unexpected then result", value)
+				}
+			}()
+			go func() {
+				defer waitGroup.Done()
+				p.Finally(func(value int, err error) error { return nil }).Await()
+			}()
+			go func() {
+				defer waitGroup.Done()
+				p.State()
+				p.IsPending()
+				p.Read()
+			}()
+		}
+		close(release)
+		waitGroup.Wait()
+		if value, ok := p.Read(); !ok || value != round {
+			t.Fatal("unexpected read", value, ok)
+		}
+	}
+}
+
+func TestAwaitAllConcurrent(t *testing.T) {
+	var promises []*Promise[int]
+	for i := 0; i < 100; i++ {
+		i := i
+		promises = append(promises, New(func() (int, error) {
+			if i%2 == 0 {
+				return 0, fmt.Errorf("error %d", i)
+			}
+			return i, nil
+		}))
+	}
+	results, errs := AwaitAll(promises)
+	if len(results) != 100 || len(errs) != 50 || results[99] != 99 {
+		t.Fatal(len(results), len(errs))
+	}
+	for idx, err := range errs {
+		if err.Error() != fmt.Sprintf("error %d", idx*2) {
+			t.Fatal("expect the errors in order", errs)
+		}
+	}
+	if value, err := Any(promises); err != nil || value%2 != 1 {
+		t.Fatal(value, err)
+	}
+}
+
+func TestRace(t *testing.T) {
+	slow := New(func() (int, error) {
+		time.Sleep(time.Millisecond * 100)
+		return 1, nil
+	})
+	fast := New(func() (int, error) {
+		return 0, errors.New("fast")
+	})
+	if _, err := Race([]*Promise[int]{slow, fast}); err == nil || err.Error() != "fast" {
+		t.Fatal("expect the first rejection to win", err)
+	}
+	if value, err := Race([]*Promise[int]{slow, New(func() (int, error) {
+		time.Sleep(time.Millisecond * 200)
+		return 0, errors.New("slow")
+	})}); err != nil || value != 1 {
+		t.Fatal("expect the first fulfillment to win", value, err)
+	}
+}
+
+type codeError struct {
+	code int
+}
+
+func (e codeError) Error() string {
+	return fmt.Sprintf("code %d", e.code)
+}
+
+func TestAny(t *testing.T) {
+	rejected := New(func() (int, error) {
+		return 0, codeError{404}
+	})
+	fulfilled := New(func() (int, error) {
+		time.Sleep(time.Millisecond * 50)
+		return 2, nil
+	})
+	if value, err := Any([]*Promise[int]{rejected, fulfilled}); err != nil || value != 2 {
+		t.Fatal(value, err)
+	}
+
+	sentinel := errors.New("sentinel")
+	_, err := Any([]*Promise[int]{
+		New(func() (int, error) {
+			time.Sleep(time.Millisecond * 50)
+			return 0, sentinel
+		}),
+		rejected,
+	})
+	var aggregate *AggregateError
+	if !errors.As(err, &aggregate) || len(aggregate.Errors) != 2 || aggregate.Errors[0] != sentinel {
+		t.Fatal("expect an AggregateError in input order", err)
+	}
+	var code codeError
+	if !errors.Is(err, sentinel) || !errors.As(err, &code) || code.code != 404 {
+		t.Fatal("expect errors.Is and errors.As to see the rejections", err)
+	}
+
+	if _, err := Any[int](nil); !errors.As(err, &aggregate) {
+		t.Fatal("expect an empty list to reject", err)
+	}
+}
+
+func TestAll(t *testing.T) {
+	values := []*Promise[int]{
+		New(func() (int, error) { return 1, nil }),
+		New(func() (int, error) { return 2, nil }),
+	}
+	if results, err := All(values); err != nil || fmt.Sprint(results) != "[1 2]" {
+		t.Fatal(results, err)
+	}
+
+	sentinel := errors.New("sentinel")
+	release := make(chan struct{})
+	defer close(release)
+	start := time.Now()
+	_, err := All([]*Promise[int]{
+		New(func() (int, error) {
+			<-release
+			return 1, nil
+		}),
+		New(func() (int, error) { return 0, sentinel }),
+	})
+	var allError *AllError
+	if !errors.As(err, &allError) || allError.Index != 1 || !errors.Is(err, sentinel) {
+		t.Fatal("expect the rejection with its index", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("expect All to fail fast")
+	}
+
+	name, age, err := All2(
+		New(func() (string, error) { return "d1y", nil }),
+		New(func() (int, error) { return 18, nil }),
+	)
+	if err != nil || name != "d1y" || age != 18 {
+		t.Fatal(name, age, err)
+	}
+	_, _, ok, err := All3(
+		New(func() (string, error) { return "", nil }),
+		New(func() (int, error) { return 0, sentinel }),
+		New(func() (bool, error) { return true, nil }),
+	)
+	if !errors.As(err, &allError) || allError.Index != 1 || ok {
+		t.Fatal("expect All3 to reject", err)
+	}
+}
+
+func TestAllSettled(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	results := AllSettled([]*Promise[int]{
+		New(func() (int, error) {
+			time.Sleep(time.Millisecond * 50)
+			return 1, nil
+		}),
+		New(func() (int, error) { return 0, sentinel }),
+	})
+	if results[0].Status != Fulfilled || results[0].Value != 1 || results[0].Reason != nil {
+		t.Fatal(results[0])
+	}
+	if results[1].Status != Rejected || results[1].Reason != sentinel || results[1].Status.String() != "rejected" {
+		t.Fatal(results[1])
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	p := New(func() (int, error) {
+		panic("boom")
+	})
+	_, err := p.Await()
+	var panicError *PanicError
+	if !errors.As(err, &panicError) || panicError.Value != "boom" || len(panicError.Stack) == 0 {
+		t.Fatal("expect a PanicError", err)
+	}
+
+	var slice []int
+	then := Then(New(func() (int, error) { return 1, nil }), func(value int) (int, error) {
+		return slice[value], nil
+	})
+	var runtimeError runtime.Error
+	if _, err := then.Await(); !errors.As(err, &runtimeError) {
+		t.Fatal("expect the runtime error to be unwrapped", err)
+	}
+
+	catch := Catch(p, func(err error) (int, error) {
+		panic(err)
+	})
+	if _, err := catch.Await(); !errors.As(err, &panicError) {
+		t.Fatal("expect a PanicError from Catch", err)
+	}
+	finally := p.Finally(func(value int, err error) error {
+		panic("finally")
+	})
+	if _, err := finally.Await(); !errors.As(err, &panicError) || panicError.Value != "finally" {
+		t.Fatal("expect a PanicError from Finally", err)
+	}
+}
+
+func TestUnhandledRejection(t *testing.T) {
+	reported := make(chan error, 10)
+	OnUnhandledRejection(func(err error) {
+		reported <- err
+	})
+	defer OnUnhandledRejection(nil)
+
+	sentinel := errors.New("unhandled")
+	handled := New(func() (int, error) { return 0, errors.New("handled") })
+	handled.Await()
+	selected := New(func() (int, error) { return 0, errors.New("selected") })
+	<-selected.Done()
+	if _, err := selected.Await(); err == nil {
+		t.Fatal("expect Await after Done to return the rejection")
+	}
+	func() {
+		unhandled := New(func() (int, error) { return 0, sentinel })
+		for unhandled.IsPending() {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	deadline := time.After(time.Second * 5)
+	for {
+		runtime.GC()
+		select {
+		case err := <-reported:
+			if err.Error() == "handled" || err.Error() == "selected" {
+				t.Fatal("expect an awaited rejection not to be reported")
+			}
+			if err == sentinel {
+				runtime.KeepAlive(handled)
+				return
+			}
+		case <-deadline:
+			t.Fatal("expect the unhandled rejection to be reported")
+		case <-time.After(time.Millisecond * 10):
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package jspromise
+
+import "sync"
+
+// Lazy returns a Promise that runs fn on the DefaultExecutor only once it is
+// used, by Await, AwaitContext, Done, Chan or as the parent of Then and the
+// other combinators. State and Read don't start it.
+func Lazy[T any](fn func() (T, error)) *Promise[T] {
+	p := newPromise[T]()
+	start := func() {
+		p.executor.Execute(func() {
+			p.settle(call(fn))
+		})
+	}
+	p.lazy.Store(&start)
+	return p
+}
+
+type MemoOptions struct {
+	// forget a key whose Promise rejected, so the next Get calls fn again
+	EvictOnError bool
+}
+
+// Memo caches the Promise of fn per key, concurrent callers of Get share the
+// call in flight like golang.org/x/sync/singleflight and later callers get the
+// settled Promise.
+type Memo[K comparable, V any] struct {
+	mutex    sync.Mutex
+	fn       func(key K) (V, error)
+	options  MemoOptions
+	promises map[K]*Promise[V]
+}
+
+func NewMemo[K comparable, V any](fn func(key K) (V, error), options ...MemoOptions) *Memo[K, V] {
+	m := &Memo[K, V]{fn: fn, promises: make(map[K]*Promise[V])}
+	if len(options) == 1 {
+		m.options = options[0]
+	}
+	return m
+}
+
+// Get returns the Promise for key, calling fn on the DefaultExecutor when
+// there is none
+func (m *Memo[K, V]) Get(key K) *Promise[V] {
+	m.mutex.Lock()
+	// the eviction callback may not have run yet when a caller saw the
+	// rejection
+	if p, ok := m.promises[key]; ok && !(m.options.EvictOnError && p.State() == Rejected) {
+		m.mutex.Unlock()
+		return p
+	}
+	p := New(func() (V, error) { return m.fn(key) })
+	m.promises[key] = p
+	m.mutex.Unlock()
+
+	if m.options.EvictOnError {
+		// after unlocking, the callback runs right away when p already failed
+		p.onSettle(func(_ V, err error) {
+			if err != nil {
+				m.evict(key, p)
+			}
+		})
+	}
+	return p
+}
+
+func (m *Memo[K, V]) evict(key K, p *Promise[V]) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.promises[key] == p {
+		delete(m.promises, key)
+	}
+}
+
+// Forget drops the Promise of key, the next Get calls fn again
+func (m *Memo[K, V]) Forget(key K) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.promises, key)
+}
+
+// Once returns a function that calls fn the first time and returns the same
+// Promise after that, a Memo without a key
+func Once[T any](fn func() (T, error), options ...MemoOptions) func() *Promise[T] {
+	m := NewMemo(func(struct{}) (T, error) { return fn() }, options...)
+	return func() *Promise[T] {
+		return m.Get(struct{}{})
+	}
+}
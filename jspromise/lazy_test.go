@@ -0,0 +1,75 @@
+package jspromise
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLazy(t *testing.T) {
+	var calls atomic.Int32
+	p := Lazy(func() (int, error) {
+		calls.Add(1)
+		return 1, nil
+	})
+	time.Sleep(time.Millisecond * 10)
+	if calls.Load() != 0 || p.State() != Pending {
+		t.Fatal("expect fn not to run before use")
+	}
+	then := Then(p, func(value int) (int, error) { return value + 1, nil })
+	if value, err := then.Await(); err != nil || value != 2 {
+		t.Fatal(value, err)
+	}
+	p.Await()
+	if calls.Load() != 1 {
+		t.Fatal("expect fn to run once", calls.Load())
+	}
+}
+
+func TestMemo(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	memo := NewMemo(func(key string) (int, error) {
+		calls.Add(1)
+		<-release
+		return len(key), nil
+	})
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			memo.Get("key")
+		}()
+	}
+	waitGroup.Wait()
+	close(release)
+	if value, err := memo.Get("key").Await(); err != nil || value != 3 || calls.Load() != 1 {
+		t.Fatal(value, err, calls.Load())
+	}
+	memo.Forget("key")
+	memo.Get("key").Await()
+	if calls.Load() != 2 {
+		t.Fatal("expect Forget to drop the key")
+	}
+
+	sentinel := errors.New("sentinel")
+	var attempts atomic.Int32
+	once := Once(func() (int, error) {
+		if attempts.Add(1) == 1 {
+			return 0, sentinel
+		}
+		return 1, nil
+	}, MemoOptions{EvictOnError: true})
+	if _, err := once().Await(); err != sentinel {
+		t.Fatal(err)
+	}
+	if value, err := once().Await(); err != nil || value != 1 {
+		t.Fatal(value, err)
+	}
+	if once() != once() {
+		t.Fatal("expect the same promise")
+	}
+}
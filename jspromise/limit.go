@@ -0,0 +1,220 @@
+package jspromise
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Limiter runs at most n of the functions passed to Limit at once, the others
+// wait in a queue without holding a goroutine. One Limiter can be shared by
+// unrelated callers to bound the work on a resource, like a host.
+type Limiter struct {
+	mutex    sync.Mutex
+	size     int
+	active   int
+	queue    []queuedTask
+	draining bool
+}
+
+type queuedTask struct {
+	task     func()
+	executor Executor
+}
+
+// NewLimiter returns a Limiter of n slots, n less than 1 means 1
+func NewLimiter(n int) *Limiter {
+	if n < 1 {
+		n = 1
+	}
+	return &Limiter{size: n}
+}
+
+// acquire runs task right away when a slot is free, or queues it to run on
+// executor once a slot is released
+func (l *Limiter) acquire(task func(), executor Executor) {
+	l.mutex.Lock()
+	if l.active < l.size && len(l.queue) == 0 {
+		l.active++
+		l.mutex.Unlock()
+		task()
+		return
+	}
+	l.queue = append(l.queue, queuedTask{task, executor})
+	l.mutex.Unlock()
+	l.drain()
+}
+
+func (l *Limiter) release() {
+	l.mutex.Lock()
+	l.active--
+	l.mutex.Unlock()
+	l.drain()
+}
+
+// drain starts queued tasks while there are free slots, a loop instead of
+// recursion because a task can release its slot before it returns. The tasks
+// go through their Executor, drain runs on whatever goroutine released a slot.
+func (l *Limiter) drain() {
+	l.mutex.Lock()
+	if l.draining {
+		l.mutex.Unlock()
+		return
+	}
+	l.draining = true
+	for l.active < l.size && len(l.queue) > 0 {
+		queued := l.queue[0]
+		l.queue[0] = queuedTask{}
+		l.queue = l.queue[1:]
+		l.active++
+		l.mutex.Unlock()
+		queued.executor.Execute(queued.task)
+		l.mutex.Lock()
+	}
+	l.draining = false
+	l.mutex.Unlock()
+}
+
+// Limit calls fn once l has a free slot and settles like the Promise it
+// returns, the slot is held until that Promise settles. fn is called right
+// away when a slot is free, a queued fn is called on the Executor of the
+// returned Promise. When ctx ends while fn is still queued the Promise rejects
+// with context.Cause(ctx) and fn is never called, once fn runs it has to watch
+// ctx itself.
+func Limit[T any](l *Limiter, ctx context.Context, fn func(ctx context.Context) *Promise[T]) *Promise[T] {
+	p := newPromise[T]()
+	stop := context.AfterFunc(ctx, func() {
+		var t T
+		p.settle(t, context.Cause(ctx))
+	})
+
+	l.acquire(func() {
+		if !stop() {
+			l.release()
+			return
+		}
+		inner, err := call(func() (*Promise[T], error) { return fn(ctx), nil })
+		if err != nil || inner == nil {
+			var t T
+			p.settle(t, err)
+			l.release()
+			return
+		}
+		inner.subscribe(func(value T, err error) {
+			p.settle(value, err)
+			l.release()
+		})
+	}, p.executor)
+
+	return p
+}
+
+// LimitFunc wraps fn so every call goes through Limit
+func LimitFunc[A any, T any](l *Limiter, fn func(ctx context.Context, arg A) *Promise[T]) func(ctx context.Context, arg A) *Promise[T] {
+	return func(ctx context.Context, arg A) *Promise[T] {
+		return Limit(l, ctx, func(ctx context.Context) *Promise[T] {
+			return fn(ctx, arg)
+		})
+	}
+}
+
+type mapConfig struct {
+	concurrency int
+	stopOnError bool
+}
+
+// A MapOption configures Map
+type MapOption func(config *mapConfig)
+
+// Concurrency limits Map to n calls of fn at once, all of them run at once by
+// default
+func Concurrency(n int) MapOption {
+	return func(config *mapConfig) {
+		config.concurrency = n
+	}
+}
+
+// StopOnError makes Map reject as soon as fn fails, with an *AllError like
+// All, and cancel the context of the calls that are still running or queued
+var StopOnError MapOption = func(config *mapConfig) {
+	config.stopOnError = true
+}
+
+// Map calls fn for every item, concurrently up to the Concurrency option, and
+// fulfills with the results in the order of items. Without StopOnError every
+// item runs and the errors are rejected together as an *AggregateError of
+// *AllError, one for every failed item in the order of items. When ctx ends
+// the items that did not start are skipped and Map rejects with
+// context.Cause(ctx).
+//
+// Use a Limiter with AwaitAll or AwaitAllSettled to get the result of every
+// item.
+func Map[T any, R any](ctx context.Context, items []T, fn func(ctx context.Context, item T, index int) (R, error), options ...MapOption) *Promise[[]R] {
+	var config mapConfig
+	for _, option := range options {
+		option(&config)
+	}
+	if config.concurrency < 1 {
+		config.concurrency = len(items)
+	}
+
+	result := newPromise[[]R]()
+	if len(items) == 0 {
+		result.settle([]R{}, nil)
+		return result
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancelCause(ctx)
+	limiter := NewLimiter(config.concurrency)
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	var remaining atomic.Int64
+	remaining.Store(int64(len(items)))
+
+	finish := func() {
+		cancel(nil)
+		if err := context.Cause(parent); err != nil {
+			result.settle(nil, err)
+			return
+		}
+		var errors []error
+		for idx, err := range errs {
+			if err != nil {
+				errors = append(errors, &AllError{Index: idx, Err: err})
+			}
+		}
+		if len(errors) > 0 {
+			result.settle(nil, &AggregateError{Errors: errors})
+			return
+		}
+		result.settle(results, nil)
+	}
+
+	for i, item := range items {
+		idx, item := i, item
+		p := Limit(limiter, ctx, func(ctx context.Context) *Promise[R] {
+			return New(func() (R, error) {
+				return fn(ctx, item, idx)
+			})
+		})
+		p.subscribe(func(value R, err error) {
+			results[idx], errs[idx] = value, err
+			if err != nil && config.stopOnError {
+				if cause := context.Cause(parent); cause != nil {
+					result.settle(nil, cause)
+				} else {
+					err := &AllError{Index: idx, Err: err}
+					// settled first so the skipped items can't win
+					result.settle(nil, err)
+					cancel(err)
+				}
+			}
+			if remaining.Add(-1) == 0 {
+				finish()
+			}
+		})
+	}
+
+	return result
+}
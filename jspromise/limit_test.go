@@ -0,0 +1,179 @@
+package jspromise
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	items := make([]int, 200)
+	for i := range items {
+		items[i] = i
+	}
+	var running, peak atomic.Int32
+	results, err := Map(context.Background(), items, func(ctx context.Context, item int, index int) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			max := peak.Load()
+			if n <= max || peak.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return item * 2, nil
+	}, Concurrency(16)).Await()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, value := range results {
+		if value != i*2 {
+			t.Fatal(results)
+		}
+	}
+	if peak.Load() > 16 {
+		t.Fatal("expect at most 16 calls at once, got", peak.Load())
+	}
+
+	if results, err := Map(context.Background(), []int{}, func(ctx context.Context, item int, index int) (int, error) {
+		return item, nil
+	}).Await(); err != nil || len(results) != 0 {
+		t.Fatal(results, err)
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	fail := func(ctx context.Context, item int, index int) (int, error) {
+		if item%2 == 1 {
+			return 0, sentinel
+		}
+		return item, nil
+	}
+	_, err := Map(context.Background(), []int{0, 1, 2, 3}, fail).Await()
+	var aggregateError *AggregateError
+	if !errors.As(err, &aggregateError) || len(aggregateError.Errors) != 2 {
+		t.Fatal(err)
+	}
+	for i, err := range aggregateError.Errors {
+		var allError *AllError
+		if !errors.As(err, &allError) || allError.Index != i*2+1 || allError.Err != sentinel {
+			t.Fatal(err)
+		}
+	}
+
+	var calls atomic.Int32
+	var canceled atomic.Bool
+	_, err = Map(context.Background(), []int{0, 1, 2, 3, 4, 5}, func(ctx context.Context, item int, index int) (int, error) {
+		calls.Add(1)
+		if item == 0 {
+			<-ctx.Done()
+			canceled.Store(true)
+			return 0, ctx.Err()
+		}
+		return fail(ctx, item, index)
+	}, Concurrency(2), StopOnError).Await()
+	var allError *AllError
+	if !errors.As(err, &allError) || allError.Index != 1 || !errors.Is(err, sentinel) {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 10)
+	if calls.Load() != 2 || !canceled.Load() {
+		t.Fatal("expect the remaining work to be canceled", calls.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*10, cancel)
+	_, err = Map(ctx, make([]int, 10), func(ctx context.Context, item int, index int) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, Concurrency(1)).Await()
+	if err != context.Canceled {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(2)
+	var running, peak atomic.Int32
+	fetch := LimitFunc(limiter, func(ctx context.Context, id int) *Promise[int] {
+		return New(func() (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				max := peak.Load()
+				if n <= max || peak.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			if id == 3 {
+				return 0, errors.New("not found")
+			}
+			return id, nil
+		})
+	})
+	promises := make([]*Promise[int], 10)
+	for i := range promises {
+		promises[i] = fetch(context.Background(), i)
+	}
+	results := AwaitAllSettled(promises)
+	for i, result := range results {
+		if (i == 3) != (result.Err != nil) || i != 3 && result.Result != i {
+			t.Fatal(results)
+		}
+	}
+	if peak.Load() > 2 {
+		t.Fatal("expect at most 2 calls at once, got", peak.Load())
+	}
+
+	limiter = NewLimiter(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	blocked, resolve, _ := WithResolvers[int]()
+	first := Limit(limiter, ctx, func(ctx context.Context) *Promise[int] { return blocked })
+	var called atomic.Bool
+	queued := Limit(limiter, ctx, func(ctx context.Context) *Promise[int] {
+		called.Store(true)
+		return Resolve(1)
+	})
+	cancel()
+	if _, err := queued.Await(); err != context.Canceled {
+		t.Fatal(err)
+	}
+	resolve(1)
+	if value, err := first.Await(); err != nil || value != 1 {
+		t.Fatal(value, err)
+	}
+	if called.Load() {
+		t.Fatal("expect the queued function not to run after cancel")
+	}
+}
+
+func TestLimiterQueuedOnExecutor(t *testing.T) {
+	limiter := NewLimiter(1)
+	first, resolve, _ := WithResolvers[int]()
+	Limit(limiter, context.Background(), func(ctx context.Context) *Promise[int] { return first })
+	unblock := make(chan struct{})
+	second := Limit(limiter, context.Background(), func(ctx context.Context) *Promise[int] {
+		<-unblock
+		return Resolve(2)
+	})
+
+	released := make(chan struct{})
+	go func() {
+		resolve(1)
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("expect a queued fn not to block the goroutine releasing the slot")
+	}
+	close(unblock)
+	if value, err := second.Await(); value != 2 || err != nil {
+		t.Fatal(value, err)
+	}
+}
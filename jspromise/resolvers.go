@@ -0,0 +1,128 @@
+package jspromise
+
+// Thenable is anything that settles like a Promise, *Promise[T] is one
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise#thenable
+type Thenable[T any] interface {
+	Await() (T, error)
+}
+
+// WithResolvers returns a pending Promise and the functions that settle it,
+// for bridging callback APIs. Only the first call of either function has an
+// effect, a nil error passed to reject rejects with ErrNilReason.
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/withResolvers
+func WithResolvers[T any]() (promise *Promise[T], resolve func(value T), reject func(err error)) {
+	p := newPromise[T]()
+	resolve = func(value T) {
+		p.settle(value, nil)
+	}
+	reject = func(err error) {
+		var t T
+		p.settle(t, rejection(err))
+	}
+	return p, resolve, reject
+}
+
+// Resolve returns a Promise fulfilled with value, without starting a goroutine
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/resolve
+func Resolve[T any](value T) *Promise[T] {
+	p := newPromise[T]()
+	p.settle(value, nil)
+	return p
+}
+
+// Reject returns a Promise rejected with err, without starting a goroutine
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/reject
+func Reject[T any](err error) *Promise[T] {
+	p := newPromise[T]()
+	var t T
+	p.settle(t, rejection(err))
+	return p
+}
+
+// From adopts the state of a Thenable, a *Promise[T] is returned as it is like
+// Promise.resolve(promise) does
+func From[T any](thenable Thenable[T]) *Promise[T] {
+	if p, ok := thenable.(*Promise[T]); ok {
+		return p
+	}
+	return New(thenable.Await)
+}
+
+// Try calls fn right away on the calling goroutine, unlike New, and returns
+// its result as a settled Promise, a panic becomes a *PanicError
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/try
+func Try[T any](fn func() (T, error)) *Promise[T] {
+	p := newPromise[T]()
+	p.settle(call(fn))
+	return p
+}
+
+// ThenPromise is Then for callbacks that return another Promise, the result
+// follows that Promise like returning a promise from then does in JS. A nil
+// Thenable fulfills with the zero value.
+func ThenPromise[T any, U any](p *Promise[T], fn func(value T) Thenable[U]) *Promise[U] {
+	result := derive[U](p)
+	p.subscribe(func(value T, err error) {
+		if err != nil {
+			var u U
+			result.settle(u, err)
+			return
+		}
+		p.executor.Execute(func() {
+			thenable, err := call(func() (Thenable[U], error) { return fn(value), nil })
+			if err != nil {
+				var u U
+				result.settle(u, err)
+				return
+			}
+			adopt(result, thenable)
+		})
+	})
+	return result
+}
+
+// Flatten unwraps a Promise of a Promise
+func Flatten[T any](p *Promise[*Promise[T]]) *Promise[T] {
+	result := derive[T](p)
+	p.subscribe(func(inner *Promise[T], err error) {
+		if err != nil {
+			var t T
+			result.settle(t, err)
+			return
+		}
+		adopt[T](result, inner)
+	})
+	return result
+}
+
+// adopt settles p like thenable, a *Promise is followed with a callback, other
+// Thenables are awaited on the Executor of p
+func adopt[T any](p *Promise[T], thenable Thenable[T]) {
+	inner, isPromise := thenable.(*Promise[T])
+	if thenable == nil || isPromise && inner == nil {
+		var t T
+		p.settle(t, nil)
+		return
+	}
+	if isPromise {
+		inner.subscribe(func(value T, err error) {
+			p.settle(value, err)
+		})
+		return
+	}
+	p.executor.Execute(func() {
+		p.settle(call(thenable.Await))
+	})
+}
+
+func rejection(err error) error {
+	if err == nil {
+		return ErrNilReason
+	}
+	return err
+}
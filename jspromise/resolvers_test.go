@@ -0,0 +1,93 @@
+package jspromise
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithResolvers(t *testing.T) {
+	p, resolve, reject := WithResolvers[string]()
+	if !p.IsPending() {
+		t.Fatal("expect a pending promise")
+	}
+	time.AfterFunc(time.Millisecond*10, func() {
+		resolve("done")
+		reject(errors.New("ignored"))
+	})
+	if value, err := p.Await(); err != nil || value != "done" {
+		t.Fatal(value, err)
+	}
+
+	rejected, _, rejectNil := WithResolvers[string]()
+	rejectNil(nil)
+	if _, err := rejected.Await(); err != ErrNilReason || rejected.State() != Rejected {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveReject(t *testing.T) {
+	if p := Resolve(1); p.State() != Fulfilled {
+		t.Fatal(p.State())
+	}
+	sentinel := errors.New("sentinel")
+	if _, err := Reject[int](sentinel).Await(); err != sentinel {
+		t.Fatal(err)
+	}
+
+	p := Resolve(2)
+	if From[int](p) != p {
+		t.Fatal("expect From to return the same promise")
+	}
+	if value, err := From[int](thenableFunc(func() (int, error) { return 3, nil })).Await(); err != nil || value != 3 {
+		t.Fatal(value, err)
+	}
+}
+
+type thenableFunc func() (int, error)
+
+func (f thenableFunc) Await() (int, error) {
+	return f()
+}
+
+func TestTry(t *testing.T) {
+	called := false
+	p := Try(func() (int, error) {
+		called = true
+		return 1, nil
+	})
+	if !called || p.State() != Fulfilled {
+		t.Fatal("expect fn to run synchronously")
+	}
+	var panicError *PanicError
+	if _, err := Try(func() (int, error) { panic("boom") }).Await(); !errors.As(err, &panicError) {
+		t.Fatal(err)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	p := ThenPromise(Resolve(2), func(value int) Thenable[string] {
+		return New(func() (string, error) {
+			time.Sleep(time.Millisecond * 10)
+			return "value", nil
+		})
+	})
+	if value, err := p.Await(); err != nil || value != "value" {
+		t.Fatal(value, err)
+	}
+	sentinel := errors.New("sentinel")
+	rejected := ThenPromise(Resolve(2), func(value int) Thenable[string] {
+		return Reject[string](sentinel)
+	})
+	if _, err := rejected.Await(); err != sentinel {
+		t.Fatal(err)
+	}
+
+	nested := Resolve(Resolve(4))
+	if value, err := Flatten(nested).Await(); err != nil || value != 4 {
+		t.Fatal(value, err)
+	}
+	if value, err := Flatten(Resolve[*Promise[int]](nil)).Await(); err != nil || value != 0 {
+		t.Fatal(value, err)
+	}
+}
@@ -0,0 +1,176 @@
+package jspromise
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryOptions configures Retry, the zero value makes 3 attempts starting
+// 100ms apart
+type RetryOptions struct {
+	// the context passed to fn, Retry rejects with context.Cause when it ends
+	Context context.Context
+	// the most calls of fn, 0 means 3 unless MaxElapsed is set
+	MaxAttempts int
+	// gives up when the next attempt would start later than this after the
+	// first one, 0 means no limit
+	MaxElapsed time.Duration
+	// the delay limit before the first retry, 100ms by default
+	InitialDelay time.Duration
+	// caps the growing delay limit, 0 means no cap
+	MaxDelay time.Duration
+	// how much the delay limit grows per attempt, 2 by default
+	Multiplier float64
+	// reports whether err is worth another attempt, every error but a
+	// *PanicError is by default
+	Retryable func(err error) bool
+	// called before waiting for attempt+1, with the error of attempt
+	OnRetry func(attempt int, err error, delay time.Duration)
+	// RealClock by default
+	Clock Clock
+	// returns a number in [0, 1) for the jitter, rand.Float64 by default
+	Rand func() float64
+}
+
+// Retry calls fn until it succeeds and settles with its result. The delay
+// before a retry is a random duration below a limit that grows exponentially,
+// the "full jitter" of https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+// When the attempts or the time run out it rejects with a *RetryError, an error
+// that is not Retryable rejects right away as it is. Waiting between attempts
+// holds no goroutine.
+func Retry[T any](fn func(ctx context.Context) (T, error), options ...RetryOptions) *Promise[T] {
+	return RetryPromise(func(ctx context.Context) *Promise[T] {
+		p := newPromise[T]()
+		p.settle(call(func() (T, error) { return fn(ctx) }))
+		return p
+	}, options...)
+}
+
+// RetryPromise is Retry for a fn that returns a Promise, like the functions
+// of Limit and LimitFunc, an attempt fails when its Promise rejects. The
+// attempts and OnRetry run on the Executor or on the goroutine that settles
+// the Promise of the last attempt.
+func RetryPromise[T any](fn func(ctx context.Context) *Promise[T], options ...RetryOptions) *Promise[T] {
+	var opts RetryOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.MaxAttempts < 1 && opts.MaxElapsed <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.InitialDelay <= 0 {
+		opts.InitialDelay = 100 * time.Millisecond
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 2
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = RealClock
+	}
+	random := opts.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+	retryable := opts.Retryable
+	if retryable == nil {
+		retryable = func(err error) bool {
+			_, panicked := err.(*PanicError)
+			return !panicked
+		}
+	}
+
+	p := newPromise[T]()
+	rejectWithCause := func() {
+		var t T
+		p.settle(t, context.Cause(ctx))
+	}
+	start := clock.Now()
+
+	var attempt func(n int)
+	// settled decides on the result of attempt n
+	settled := func(n int, value T, err error) {
+		if err == nil {
+			p.settle(value, nil)
+			return
+		}
+		var t T
+		switch {
+		case ctx.Err() != nil:
+			rejectWithCause()
+			return
+		case !retryable(err):
+			p.settle(t, err)
+			return
+		case opts.MaxAttempts > 0 && n >= opts.MaxAttempts:
+			p.settle(t, &RetryError{Attempts: n, Err: err})
+			return
+		}
+
+		limit := float64(opts.InitialDelay) * math.Pow(opts.Multiplier, float64(n-1))
+		if opts.MaxDelay > 0 && limit > float64(opts.MaxDelay) {
+			limit = float64(opts.MaxDelay)
+		}
+		delay := time.Duration(random() * limit)
+		if opts.MaxElapsed > 0 && clock.Now().Add(delay).Sub(start) > opts.MaxElapsed {
+			p.settle(t, &RetryError{Attempts: n, Err: err})
+			return
+		}
+		if opts.OnRetry != nil {
+			opts.OnRetry(n, err, delay)
+		}
+
+		// whichever of the timer and ctx comes first claims the wait
+		var claimed atomic.Bool
+		var mutex sync.Mutex
+		var stopTimer, stopContext func() bool
+		mutex.Lock()
+		defer mutex.Unlock()
+		stopTimer = clock.AfterFunc(delay, func() {
+			mutex.Lock()
+			stop := stopContext
+			mutex.Unlock()
+			if claimed.CompareAndSwap(false, true) {
+				stop()
+				attempt(n + 1)
+			}
+		})
+		stopContext = context.AfterFunc(ctx, func() {
+			mutex.Lock()
+			stop := stopTimer
+			mutex.Unlock()
+			if claimed.CompareAndSwap(false, true) {
+				stop()
+				rejectWithCause()
+			}
+		})
+	}
+	attempt = func(n int) {
+		p.executor.Execute(func() {
+			if ctx.Err() != nil {
+				rejectWithCause()
+				return
+			}
+			inner, err := call(func() (*Promise[T], error) { return fn(ctx), nil })
+			if err != nil || inner == nil {
+				var t T
+				settled(n, t, err)
+				return
+			}
+			inner.subscribe(func(value T, err error) {
+				settled(n, value, err)
+			})
+		})
+	}
+	attempt(1)
+
+	return p
+}
@@ -0,0 +1,128 @@
+package jspromise
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	SetDefaultExecutor(Inline)
+	defer SetDefaultExecutor(nil)
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	sentinel := errors.New("sentinel")
+	var delays []time.Duration
+	calls := 0
+	p := Retry(func(ctx context.Context) (int, error) {
+		calls++
+		if calls < 4 {
+			return 0, sentinel
+		}
+		return calls, nil
+	}, RetryOptions{
+		MaxAttempts:  5,
+		InitialDelay: time.Second,
+		MaxDelay:     3 * time.Second,
+		Clock:        clock,
+		Rand:         func() float64 { return 0.5 },
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			delays = append(delays, delay)
+		},
+	})
+	if calls != 1 || !p.IsPending() {
+		t.Fatal("expect to wait after the first attempt", calls)
+	}
+	clock.Advance(1500 * time.Millisecond)
+	if calls != 3 {
+		t.Fatal("expect the retries after 500ms and 1s to run", calls)
+	}
+	clock.Advance(1500 * time.Millisecond)
+	if value, err := p.Await(); err != nil || value != 4 {
+		t.Fatal(value, err)
+	}
+	cases := []time.Duration{500 * time.Millisecond, time.Second, 1500 * time.Millisecond}
+	if len(delays) != len(cases) {
+		t.Fatal(delays)
+	}
+	for idx, delay := range cases {
+		if delays[idx] != delay {
+			t.Fatal(delays)
+		}
+	}
+}
+
+func TestRetryLimits(t *testing.T) {
+	SetDefaultExecutor(Inline)
+	defer SetDefaultExecutor(nil)
+
+	sentinel := errors.New("sentinel")
+	fail := func(ctx context.Context) (int, error) {
+		return 0, sentinel
+	}
+	clock := NewFakeClock(time.Unix(0, 0))
+	p := Retry(fail, RetryOptions{Clock: clock, Rand: func() float64 { return 0.99 }})
+	clock.Advance(time.Minute)
+	var retryError *RetryError
+	if _, err := p.Await(); !errors.As(err, &retryError) || retryError.Attempts != 3 || !errors.Is(err, sentinel) {
+		t.Fatal(err)
+	}
+
+	p = Retry(fail, RetryOptions{MaxElapsed: 10 * time.Second, Clock: clock, Rand: func() float64 { return 0.99 }})
+	clock.Advance(time.Minute)
+	if _, err := p.Await(); !errors.As(err, &retryError) || retryError.Attempts != 7 {
+		t.Fatal(err)
+	}
+
+	permanent := errors.New("permanent")
+	calls := 0
+	p = Retry(func(ctx context.Context) (int, error) {
+		calls++
+		return 0, permanent
+	}, RetryOptions{Clock: clock, Retryable: func(err error) bool { return err != permanent }})
+	if _, err := p.Await(); err != permanent || calls != 1 {
+		t.Fatal(err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p = Retry(fail, RetryOptions{Context: ctx, Clock: clock})
+	if clock.Pending() != 1 {
+		t.Fatal("expect a scheduled retry")
+	}
+	cancel()
+	if _, err := p.Await(); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if clock.Pending() != 0 {
+		t.Fatal("expect the retry to be stopped")
+	}
+}
+
+func TestRetryPromise(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	calls := 0
+	fetch := LimitFunc(NewLimiter(1), func(ctx context.Context, n int) *Promise[int] {
+		return New(func() (int, error) {
+			calls++
+			if calls < n {
+				return 0, sentinel
+			}
+			return calls, nil
+		})
+	})
+	p := RetryPromise(func(ctx context.Context) *Promise[int] {
+		return fetch(ctx, 3)
+	}, RetryOptions{InitialDelay: time.Millisecond})
+	if value, err := p.Await(); err != nil || value != 3 {
+		t.Fatal(value, err)
+	}
+
+	_, err := RetryPromise(func(ctx context.Context) *Promise[int] {
+		return Reject[int](sentinel)
+	}, RetryOptions{MaxAttempts: 2, InitialDelay: time.Millisecond}).Await()
+	var retryError *RetryError
+	if !errors.As(err, &retryError) || retryError.Attempts != 2 || retryError.Err != sentinel {
+		t.Fatal(err)
+	}
+}
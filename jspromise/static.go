@@ -1,6 +1,9 @@
 package jspromise
 
-import "sync"
+import (
+	"runtime/debug"
+	"sync"
+)
 
 type promiseList[T any] []*Promise[T]
 
@@ -9,81 +12,114 @@ type AwaitAllSettledResult[T any] struct {
 	Err    error
 }
 
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/allSettled#返回值
+type SettledResult[T any] struct {
+	// Fulfilled or Rejected
+	Status State
+	Value  T
+	Reason error
+}
+
 // Creates new goroutine wrapped in a Promise struct. Promise struct exposes methods for, waiting
-// for the routine to finish, or attaching listener functions.
+// for the routine to finish, or attaching listener functions. fn runs on the
+// DefaultExecutor.
 func New[T any](fn func() (T, error)) *Promise[T] {
-	p := Promise[T]{
-		mutex:      sync.Mutex{},
-		isFinished: false,
-		awaiters:   []chan awaiterResult[T]{},
-		err:        nil,
-	}
+	return NewWithExecutor(DefaultExecutor(), fn)
+}
+
+// NewWithExecutor is New with fn and the continuations of the Promise on
+// executor instead of the DefaultExecutor
+func NewWithExecutor[T any](executor Executor, fn func() (T, error)) *Promise[T] {
+	p := newPromise[T]()
+	p.executor = executor
+
+	executor.Execute(func() {
+		p.settle(call(fn))
+	})
+
+	return p
+}
 
-	go (func() {
-		result, err := fn()
-		p.finalize(result, err)
-	})()
+// call runs fn and turns a panic into a *PanicError, like a throw in an
+// executor rejects the promise in JS
+func call[T any](fn func() (T, error)) (result T, err error) {
+	defer recoverPanic(&result, &err)
+	return fn()
+}
 
-	return &p
+// callWith is call for callbacks that take an argument, it saves a closure
+// per continuation
+func callWith[A any, T any](fn func(arg A) (T, error), arg A) (result T, err error) {
+	defer recoverPanic(&result, &err)
+	return fn(arg)
+}
+
+func recoverPanic[T any](result *T, err *error) {
+	if value := recover(); value != nil {
+		var t T
+		*result, *err = t, &PanicError{Value: value, Stack: debug.Stack()}
+	}
 }
 
 // Creates a new Promise that will run once this Promise
 // goroutine finishes without error.
 func Then[T any, U any](p *Promise[T], fn func(value T) (U, error)) *Promise[U] {
-	return New(func() (U, error) {
-		r, parentError := p.Await()
-
-		if parentError == nil {
-			return fn(r)
+	result := derive[U](p)
+	p.subscribe(func(value T, err error) {
+		if err != nil {
+			var u U
+			result.settle(u, err)
+			return
 		}
-
-		var u U
-		return u, parentError
+		p.executor.Execute(func() {
+			result.settle(callWith(fn, value))
+		})
 	})
+	return result
 }
 
 // Creates a new Promise that will run once this Promise
 // goroutine finishes with error.
 func Catch[T any, U any](p *Promise[T], fn func(err error) (U, error)) *Promise[U] {
-	return New(func() (U, error) {
-		_, parentError := p.Await()
-
-		if parentError != nil {
-			return fn(parentError)
+	result := derive[U](p)
+	p.subscribe(func(_ T, err error) {
+		if err == nil {
+			var u U
+			result.settle(u, nil)
+			return
 		}
-
-		var u U
-		return u, parentError
+		p.executor.Execute(func() {
+			result.settle(callWith(fn, err))
+		})
 	})
+	return result
 }
 
 // Waits for all of the Promises in the provided list to resolve, and returns a list
-// with each of those Promises result.
+// with each of those Promises result. The errors are in the order of the list.
 func AwaitAll[T any](promises promiseList[T]) ([]T, []error) {
 	results := make([]T, len(promises))
-	var errors []error
+	errs := make([]error, len(promises))
 
 	var waitGroup sync.WaitGroup
+	waitGroup.Add(len(promises))
 
 	for i, p := range promises {
 		idx := i
-		waitGroup.Add(1)
-
-		Then(p, func(value T) (T, error) {
-			results[idx] = value
+		p.subscribe(func(value T, err error) {
+			results[idx], errs[idx] = value, err
 			waitGroup.Done()
-			return value, nil
-		})
-
-		Catch(p, func(e error) (error, error) {
-			waitGroup.Done()
-			errors = append(errors, e)
-			return e, nil
 		})
 	}
 
 	waitGroup.Wait()
 
+	var errors []error
+	for _, err := range errs {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
 	return results, errors
 }
 
@@ -93,15 +129,13 @@ func AwaitAllSettled[T any](promises promiseList[T]) []AwaitAllSettledResult[T]
 	results := make([]AwaitAllSettledResult[T], len(promises))
 
 	var waitGroup sync.WaitGroup
+	waitGroup.Add(len(promises))
 
 	for i, p := range promises {
 		idx := i
-		waitGroup.Add(1)
-
-		p.Finally(func(value T, err error) error {
+		p.subscribe(func(value T, err error) {
 			results[idx] = AwaitAllSettledResult[T]{Result: value, Err: err}
 			waitGroup.Done()
-			return nil
 		})
 	}
 
@@ -110,29 +144,167 @@ func AwaitAllSettled[T any](promises promiseList[T]) []AwaitAllSettledResult[T]
 	return results
 }
 
-// Waits for any of the provided Promises to resolve, and returns it's result.
+// Waits for the first of the provided Promises to settle, and returns it's
+// result or error. Like Promise.race([]) it never returns for an empty list.
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/race
 func Race[T any](promises promiseList[T]) (T, error) {
-	channel := make(chan T)
-	var err error = nil
-
-	failedCount := 0
+	// only the winner is sent, the losers don't block
+	channel := make(chan awaiterResult[T], 1)
 
 	for _, p := range promises {
-		Then(p, func(value T) (T, error) {
-			channel <- value
-			return value, nil
+		p.subscribe(func(value T, err error) {
+			select {
+			case channel <- awaiterResult[T]{value: value, err: err}:
+			default:
+			}
+		})
+	}
+
+	result := <-channel
+	return result.value, result.err
+}
+
+// Waits for the first of the provided Promises to fulfill, and returns it's
+// result. When all of them reject the error is an *AggregateError with the
+// errors in the order of the list.
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/any
+func Any[T any](promises promiseList[T]) (T, error) {
+	type indexedResult struct {
+		awaiterResult[T]
+		idx int
+	}
+	channel := make(chan indexedResult, len(promises))
+
+	for i, p := range promises {
+		idx := i
+		p.subscribe(func(value T, err error) {
+			channel <- indexedResult{awaiterResult[T]{value: value, err: err}, idx}
 		})
+	}
+
+	errors := make([]error, len(promises))
+	for range promises {
+		result := <-channel
+		if result.err == nil {
+			return result.value, nil
+		}
+		errors[result.idx] = result.err
+	}
+
+	var t T
+	return t, &AggregateError{Errors: errors}
+}
+
+// Waits for all of the provided Promises to fulfill and returns their results
+// in order. It returns as soon as one of them rejects, with an *AllError that
+// wraps the error, the other Promises keep running.
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/all
+func All[T any](promises promiseList[T]) ([]T, error) {
+	return allOf(promises).Await()
+}
+
+// All2 is All for two Promises of different types
+func All2[A, B any](a *Promise[A], b *Promise[B]) (A, B, error) {
+	var resultA A
+	var resultB B
+	err := waitAll(2, func(idx int, done func(err error)) {
+		if idx == 0 {
+			a.subscribe(func(value A, err error) {
+				resultA = value
+				done(err)
+			})
+		} else {
+			b.subscribe(func(value B, err error) {
+				resultB = value
+				done(err)
+			})
+		}
+	})
+	if err != nil {
+		var zeroA A
+		var zeroB B
+		return zeroA, zeroB, err
+	}
+	return resultA, resultB, nil
+}
 
-		Catch(p, func(e error) (error, error) {
-			failedCount++
-			if failedCount == len(promises) {
-				err = e
-				var t T
-				channel <- t
+// All3 is All for three Promises of different types
+func All3[A, B, C any](a *Promise[A], b *Promise[B], c *Promise[C]) (A, B, C, error) {
+	var resultA A
+	var resultB B
+	var resultC C
+	err := waitAll(3, func(idx int, done func(err error)) {
+		switch idx {
+		case 0:
+			a.subscribe(func(value A, err error) {
+				resultA = value
+				done(err)
+			})
+		case 1:
+			b.subscribe(func(value B, err error) {
+				resultB = value
+				done(err)
+			})
+		default:
+			c.subscribe(func(value C, err error) {
+				resultC = value
+				done(err)
+			})
+		}
+	})
+	if err != nil {
+		var zeroA A
+		var zeroB B
+		var zeroC C
+		return zeroA, zeroB, zeroC, err
+	}
+	return resultA, resultB, resultC, nil
+}
+
+// waitAll subscribes to every index and returns the first error passed to done
+func waitAll(n int, subscribe func(idx int, done func(err error))) error {
+	// buffered so the remaining callbacks don't block after an early return
+	channel := make(chan *AllError, n)
+	for i := 0; i < n; i++ {
+		idx := i
+		subscribe(idx, func(err error) {
+			if err != nil {
+				channel <- &AllError{Index: idx, Err: err}
+				return
 			}
-			return e, nil
+			channel <- nil
 		})
 	}
+	for i := 0; i < n; i++ {
+		if err := <-channel; err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return <-channel, err
+// Waits for all of the provided Promises to settle, and returns their
+// outcomes in the order of the list.
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Promise/allSettled
+func AllSettled[T any](promises promiseList[T]) []SettledResult[T] {
+	results := make([]SettledResult[T], len(promises))
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(len(promises))
+	for i, p := range promises {
+		idx := i
+		p.subscribe(func(value T, err error) {
+			defer waitGroup.Done()
+			if err != nil {
+				results[idx] = SettledResult[T]{Status: Rejected, Reason: err}
+				return
+			}
+			results[idx] = SettledResult[T]{Status: Fulfilled, Value: value}
+		})
+	}
+	waitGroup.Wait()
+	return results
 }
@@ -0,0 +1,153 @@
+package jsstreams
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTrailingData is the error of a DecompressionStream with bytes after the
+// end of the compressed data
+var ErrTrailingData = errors.New("jsstreams: data after the end of the compressed stream")
+
+func checkFormat(format string) error {
+	switch format {
+	case "gzip", "deflate", "deflate-raw":
+		return nil
+	}
+	return fmt.Errorf("jsstreams: unsupported compression format %q", format)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/CompressionStream/CompressionStream
+//
+// format is "gzip", "deflate" (zlib) or "deflate-raw"
+func NewCompressionStream(format string) (*TransformStream[[]byte, []byte], error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch format {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "deflate-raw":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	emit := func(controller *TransformStreamDefaultController[[]byte]) error {
+		if buf.Len() == 0 {
+			return nil
+		}
+		chunk := bytes.Clone(buf.Bytes())
+		buf.Reset()
+		return controller.Enqueue(chunk)
+	}
+	return NewTransformStream(Transformer[[]byte, []byte]{
+		Transform: func(chunk []byte, controller *TransformStreamDefaultController[[]byte]) error {
+			if _, err := w.Write(chunk); err != nil {
+				return err
+			}
+			return emit(controller)
+		},
+		Flush: func(controller *TransformStreamDefaultController[[]byte]) error {
+			if err := w.Close(); err != nil {
+				return err
+			}
+			return emit(controller)
+		},
+	}), nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/DecompressionStream/DecompressionStream
+//
+// format is "gzip", "deflate" (zlib) or "deflate-raw". The data is inflated
+// on its own goroutine which waits for the readable side like a write does.
+func NewDecompressionStream(format string) (*TransformStream[[]byte, []byte], error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	started := false
+	start := func(controller *TransformStreamDefaultController[[]byte]) {
+		if started {
+			return
+		}
+		started = true
+		go func() {
+			err := inflate(format, bufio.NewReader(pr), controller)
+			if err != nil {
+				pr.CloseWithError(err)
+				controller.Error(err)
+			}
+			done <- err
+		}()
+	}
+	return NewTransformStream(Transformer[[]byte, []byte]{
+		Transform: func(chunk []byte, controller *TransformStreamDefaultController[[]byte]) error {
+			start(controller)
+			_, err := pw.Write(chunk)
+			return err
+		},
+		Flush: func(controller *TransformStreamDefaultController[[]byte]) error {
+			start(controller)
+			pw.Close()
+			return <-done
+		},
+		Cancel: func(reason error) error {
+			pw.CloseWithError(reason)
+			return nil
+		},
+	}), nil
+}
+
+// inflate reads the compressed data of r and enqueues what it inflates
+func inflate(format string, r *bufio.Reader, controller *TransformStreamDefaultController[[]byte]) error {
+	var decompressor io.Reader
+	var err error
+	switch format {
+	case "gzip":
+		decompressor, err = gzip.NewReader(r)
+	case "deflate":
+		decompressor, err = zlib.NewReader(r)
+	case "deflate-raw":
+		decompressor = flate.NewReader(r)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	for {
+		buf := make([]byte, chunkSize)
+		n, err := decompressor.Read(buf)
+		if n > 0 {
+			if err := controller.waitForDemand(); err != nil {
+				return err
+			}
+			if err := controller.Enqueue(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// gzip reads every member, the others stop at the end of theirs
+	if _, err := r.ReadByte(); err == nil {
+		return ErrTrailingData
+	} else if err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package jsstreams
+
+import (
+	"io"
+)
+
+// the most FromReader reads for one chunk
+const chunkSize = 32 * 1024
+
+// FromReader is a ReadableStream of the bytes of r, it reads a chunk only when
+// the queue is empty so a slow consumer slows the reads down. r is closed when
+// it ends or the stream is canceled if it is an io.Closer.
+func FromReader(r io.Reader) *ReadableStream[[]byte] {
+	closeReader := func() error {
+		if closer, ok := r.(io.Closer); ok {
+			return closer.Close()
+		}
+		return nil
+	}
+	return NewReadableStream(UnderlyingSource[[]byte]{
+		Pull: func(controller *ReadableStreamDefaultController[[]byte]) error {
+			buf := make([]byte, chunkSize)
+			n, err := r.Read(buf)
+			if n > 0 {
+				controller.Enqueue(buf[:n])
+			}
+			switch err {
+			case nil:
+				return nil
+			case io.EOF:
+				controller.Close()
+				closeReader()
+				return nil
+			}
+			closeReader()
+			return err
+		},
+		Cancel: func(reason error) error {
+			return closeReader()
+		},
+	}, QueuingStrategy[[]byte]{HighWaterMark: 0})
+}
+
+// ToReader reads the chunks of s as an io.Reader, it locks s on the first
+// Read. Close cancels s.
+func ToReader(s *ReadableStream[[]byte]) io.ReadCloser {
+	return &streamReader{stream: s}
+}
+
+type streamReader struct {
+	stream *ReadableStream[[]byte]
+	reader *ReadableStreamDefaultReader[[]byte]
+	chunk  []byte
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		reader, err := r.stream.GetReader()
+		if err != nil {
+			return 0, err
+		}
+		r.reader = reader
+	}
+	for len(r.chunk) == 0 {
+		chunk, done, err := r.reader.Read()
+		if err != nil {
+			return 0, err
+		}
+		if done {
+			return 0, io.EOF
+		}
+		r.chunk = chunk
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+func (r *streamReader) Close() error {
+	r.chunk = nil
+	if r.reader == nil {
+		return r.stream.Cancel(nil)
+	}
+	return r.reader.Cancel(nil)
+}
+
+// FromWriter is a WritableStream that writes its chunks to w, w is closed with
+// the stream if it is an io.Closer.
+func FromWriter(w io.Writer) *WritableStream[[]byte] {
+	closeWriter := func() error {
+		if closer, ok := w.(io.Closer); ok {
+			return closer.Close()
+		}
+		return nil
+	}
+	return NewWritableStream(UnderlyingSink[[]byte]{
+		Write: func(chunk []byte, controller *WritableStreamDefaultController) error {
+			_, err := w.Write(chunk)
+			return err
+		},
+		Close: closeWriter,
+		Abort: func(reason error) error {
+			return closeWriter()
+		},
+	})
+}
+
+// ToWriter writes to s as an io.Writer, it locks s on the first Write. Close
+// closes s.
+func ToWriter(s *WritableStream[[]byte]) io.WriteCloser {
+	return &streamWriter{stream: s}
+}
+
+type streamWriter struct {
+	stream *WritableStream[[]byte]
+	writer *WritableStreamDefaultWriter[[]byte]
+}
+
+func (w *streamWriter) lock() error {
+	if w.writer != nil {
+		return nil
+	}
+	writer, err := w.stream.GetWriter()
+	if err != nil {
+		return err
+	}
+	w.writer = writer
+	return nil
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if err := w.lock(); err != nil {
+		return 0, err
+	}
+	// the sink may keep the chunk, p is the caller's to reuse
+	if err := w.writer.Write(append([]byte(nil), p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *streamWriter) Close() error {
+	if err := w.lock(); err != nil {
+		return err
+	}
+	return w.writer.Close()
+}
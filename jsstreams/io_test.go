@@ -0,0 +1,86 @@
+package jsstreams
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type trackedReader struct {
+	io.Reader
+	reads  int
+	closed bool
+}
+
+func (r *trackedReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func (r *trackedReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestFromReader(t *testing.T) {
+	data := strings.Repeat("jslike", chunkSize)
+	source := &trackedReader{Reader: strings.NewReader(data)}
+	s := FromReader(source)
+	reader, _ := s.GetReader()
+	chunk, _, err := reader.Read()
+	if err != nil || len(chunk) != chunkSize {
+		t.Fatal(len(chunk), err)
+	}
+	if source.reads != 1 {
+		t.Fatal("expect to read only what was asked for, got", source.reads)
+	}
+	reader.ReleaseLock()
+
+	body, err := io.ReadAll(ToReader(s))
+	if err != nil || string(body) != data[chunkSize:] {
+		t.Fatal(len(body), err)
+	}
+	if !source.closed {
+		t.Fatal("expect the reader to be closed at EOF")
+	}
+
+	source = &trackedReader{Reader: strings.NewReader(data)}
+	r := ToReader(FromReader(source))
+	r.Read(make([]byte, 10))
+	if err := r.Close(); err != nil || !source.closed {
+		t.Fatal(err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestFromWriter(t *testing.T) {
+	var buf bytes.Buffer
+	data := strings.Repeat("jslike", chunkSize)
+	if err := FromReader(strings.NewReader(data)).PipeTo(FromWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != data {
+		t.Fatal(buf.Len())
+	}
+
+	buf.Reset()
+	w := ToWriter(FromWriter(&buf))
+	if _, err := io.Copy(w, strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil || buf.String() != data {
+		t.Fatal(err, buf.Len())
+	}
+
+	s := FromReader(strings.NewReader(data))
+	if err := s.PipeTo(FromWriter(failingWriter{})); err == nil || err.Error() != "disk full" {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,120 @@
+package jsstreams
+
+import (
+	"sync"
+
+	"d1y.io/jslike/jspromise"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/pipeTo#options
+type PipeOptions struct {
+	// keeps dest open when the stream closes
+	PreventClose bool
+	// keeps dest writable when the stream errors
+	PreventAbort bool
+	// keeps the stream readable when dest errors
+	PreventCancel bool
+	// stops the pipe, dest is aborted and the stream canceled with the reason
+	// unless prevented
+	Signal *jspromise.AbortSignal
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/pipeTo
+//
+// PipeTo blocks until every chunk is written and dest is closed. A Write
+// waits for the sink, so the stream is read no faster than dest takes it.
+func (s *ReadableStream[T]) PipeTo(dest *WritableStream[T], options ...PipeOptions) error {
+	reader, writer, err := lockPipe(s, dest)
+	if err != nil {
+		return err
+	}
+	return pipe(reader, writer, options...)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/pipeThrough
+//
+// It pipes s to the writable side of transform on a new goroutine and returns
+// the readable side, a method can't have the type parameter of the result.
+func PipeThrough[T, U any](s *ReadableStream[T], transform *TransformStream[T, U], options ...PipeOptions) (*ReadableStream[U], error) {
+	reader, writer, err := lockPipe(s, transform.Writable())
+	if err != nil {
+		return nil, err
+	}
+	go pipe(reader, writer, options...)
+	return transform.Readable(), nil
+}
+
+func lockPipe[T any](s *ReadableStream[T], dest *WritableStream[T]) (*ReadableStreamDefaultReader[T], *WritableStreamDefaultWriter[T], error) {
+	reader, err := s.GetReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	writer, err := dest.GetWriter()
+	if err != nil {
+		reader.ReleaseLock()
+		return nil, nil, err
+	}
+	return reader, writer, nil
+}
+
+// https://streams.spec.whatwg.org/#readable-stream-pipe-to
+func pipe[T any](reader *ReadableStreamDefaultReader[T], writer *WritableStreamDefaultWriter[T], options ...PipeOptions) error {
+	var opts PipeOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	defer reader.ReleaseLock()
+	defer writer.ReleaseLock()
+
+	var mutex sync.Mutex
+	var abortReason error
+	aborted := func() error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return abortReason
+	}
+	if opts.Signal != nil {
+		stop := opts.Signal.OnAbort(func(reason error) {
+			mutex.Lock()
+			abortReason = reason
+			mutex.Unlock()
+			if !opts.PreventAbort {
+				writer.Abort(reason)
+			}
+			if !opts.PreventCancel {
+				reader.Cancel(reason)
+			}
+			// wakes a Read waiting for a chunk
+			reader.ReleaseLock()
+		})
+		defer stop()
+	}
+
+	for {
+		chunk, done, err := reader.Read()
+		if reason := aborted(); reason != nil {
+			return reason
+		}
+		switch {
+		case err != nil:
+			if !opts.PreventAbort {
+				writer.Abort(err)
+			}
+			return err
+		case done:
+			if opts.PreventClose {
+				return nil
+			}
+			return writer.Close()
+		}
+		if err := writer.Write(chunk); err != nil {
+			if reason := aborted(); reason != nil {
+				return reason
+			}
+			if !opts.PreventCancel {
+				reader.Cancel(err)
+			}
+			return err
+		}
+	}
+}
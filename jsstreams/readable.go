@@ -0,0 +1,495 @@
+package jsstreams
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"d1y.io/jslike/jspromise"
+)
+
+var (
+	// ErrLocked is returned when a stream already has a reader or a writer
+	ErrLocked = errors.New("jsstreams: stream is locked")
+	// ErrClosed is returned when enqueuing to or writing to a closed stream
+	ErrClosed = errors.New("jsstreams: stream is closed")
+	// ErrReleased is returned by a reader or writer after ReleaseLock
+	ErrReleased = errors.New("jsstreams: lock was released")
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/ReadableStream#underlyingsource
+//
+// The functions run without a lock held, Pull never runs twice at once.
+type UnderlyingSource[T any] struct {
+	// called once by NewReadableStream, an error errors the stream
+	Start func(controller *ReadableStreamDefaultController[T]) error
+	// called on its own goroutine whenever the queue is below the high water
+	// mark or a reader waits, until it enqueues, closes or errors
+	Pull func(controller *ReadableStreamDefaultController[T]) error
+	// called when a consumer cancels the stream
+	Cancel func(reason error) error
+}
+
+type queueEntry[T any] struct {
+	chunk T
+	size  float64
+}
+
+type streamState int
+
+const (
+	stateOpen streamState = iota
+	stateClosed
+	stateErrored
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream
+type ReadableStream[T any] struct {
+	mutex          sync.Mutex
+	cond           sync.Cond
+	state          streamState
+	storedError    error
+	queue          []queueEntry[T]
+	queueTotal     float64
+	closeRequested bool
+	reader         *ReadableStreamDefaultReader[T]
+	disturbed      bool
+	// readers waiting in Read
+	waiting   int
+	started   bool
+	pulling   bool
+	pullAgain bool
+	// closed once the stream is closed or errored
+	done chan struct{}
+	// Closed promises to settle once s.mutex is unlocked, see unlock
+	settling []func()
+
+	source        UnderlyingSource[T]
+	highWaterMark float64
+	size          func(chunk T) float64
+	controller    *ReadableStreamDefaultController[T]
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/ReadableStream
+func NewReadableStream[T any](source UnderlyingSource[T], strategy ...QueuingStrategy[T]) *ReadableStream[T] {
+	s := &ReadableStream[T]{source: source, highWaterMark: 1, done: make(chan struct{})}
+	s.cond.L = &s.mutex
+	if len(strategy) > 0 {
+		s.highWaterMark = strategy[0].HighWaterMark
+		s.size = strategy[0].Size
+	}
+	s.controller = &ReadableStreamDefaultController[T]{stream: s}
+	if source.Start != nil {
+		if err := source.Start(s.controller); err != nil {
+			s.controller.Error(err)
+		}
+	}
+	s.mutex.Lock()
+	s.started = true
+	s.pullIfNeeded()
+	s.mutex.Unlock()
+	return s
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/locked
+func (s *ReadableStream[T]) Locked() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.reader != nil
+}
+
+// Disturbed reports whether the stream was read from or canceled, like the
+// bodyUsed of fetch bodies
+func (s *ReadableStream[T]) Disturbed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.disturbed
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/cancel
+func (s *ReadableStream[T]) Cancel(reason error) error {
+	if s.Locked() {
+		return ErrLocked
+	}
+	return s.cancel(reason)
+}
+
+func (s *ReadableStream[T]) cancel(reason error) error {
+	s.mutex.Lock()
+	s.disturbed = true
+	switch s.state {
+	case stateClosed:
+		s.mutex.Unlock()
+		return nil
+	case stateErrored:
+		s.mutex.Unlock()
+		return s.storedError
+	}
+	s.queue = nil
+	s.queueTotal = 0
+	s.finishClose()
+	s.unlock()
+	if s.source.Cancel != nil {
+		return s.source.Cancel(reason)
+	}
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/getReader
+func (s *ReadableStream[T]) GetReader() (*ReadableStreamDefaultReader[T], error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.reader != nil {
+		return nil, ErrLocked
+	}
+	s.reader = &ReadableStreamDefaultReader[T]{stream: s}
+	return s.reader, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/tee
+//
+// Both branches get every chunk, the slower one buffers what the faster one
+// read ahead. The stream is canceled once both branches are.
+func (s *ReadableStream[T]) Tee() (*ReadableStream[T], *ReadableStream[T], error) {
+	reader, err := s.GetReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	var mutex sync.Mutex
+	var reading, readAgain bool
+	var canceled [2]bool
+	var reasons [2]error
+	var branches [2]*ReadableStream[T]
+
+	pull := func(*ReadableStreamDefaultController[T]) error {
+		mutex.Lock()
+		if reading {
+			readAgain = true
+			mutex.Unlock()
+			return nil
+		}
+		reading = true
+		mutex.Unlock()
+		for {
+			chunk, done, err := reader.Read()
+			mutex.Lock()
+			for idx, branch := range branches {
+				if canceled[idx] {
+					continue
+				}
+				switch {
+				case err != nil:
+					branch.controller.Error(err)
+				case done:
+					branch.controller.Close()
+				default:
+					branch.controller.Enqueue(chunk)
+				}
+			}
+			again := readAgain && err == nil && !done
+			readAgain = false
+			if !again {
+				reading = false
+				mutex.Unlock()
+				return nil
+			}
+			mutex.Unlock()
+		}
+	}
+	cancel := func(idx int) func(reason error) error {
+		return func(reason error) error {
+			mutex.Lock()
+			canceled[idx] = true
+			reasons[idx] = reason
+			both := canceled[0] && canceled[1]
+			mutex.Unlock()
+			if both {
+				return reader.Cancel(errors.Join(reasons[0], reasons[1]))
+			}
+			return nil
+		}
+	}
+	mutex.Lock()
+	for idx := range branches {
+		branches[idx] = NewReadableStream(UnderlyingSource[T]{Pull: pull, Cancel: cancel(idx)})
+	}
+	mutex.Unlock()
+	return branches[0], branches[1], nil
+}
+
+// pullIfNeeded starts Pull when the stream wants chunks, s.mutex is held
+func (s *ReadableStream[T]) pullIfNeeded() {
+	if s.state != stateOpen || !s.started || s.closeRequested || s.source.Pull == nil {
+		return
+	}
+	if !s.wantsChunksLocked() {
+		return
+	}
+	if s.pulling {
+		s.pullAgain = true
+		return
+	}
+	s.pulling = true
+	go s.pull()
+}
+
+func (s *ReadableStream[T]) pull() {
+	err := s.source.Pull(s.controller)
+	s.mutex.Lock()
+	defer s.unlock()
+	s.pulling = false
+	if err != nil {
+		s.errorStream(err)
+		return
+	}
+	if s.pullAgain {
+		s.pullAgain = false
+		s.pullIfNeeded()
+	}
+}
+
+// wantsChunks reports whether a reader waits or the queue is below the high
+// water mark
+func (s *ReadableStream[T]) wantsChunks() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.wantsChunksLocked()
+}
+
+func (s *ReadableStream[T]) wantsChunksLocked() bool {
+	// a waiting reader that a queued chunk will serve asks for nothing
+	return s.waiting > len(s.queue) || s.desiredSize() > 0
+}
+
+func (s *ReadableStream[T]) desiredSize() float64 {
+	switch s.state {
+	case stateOpen:
+		return s.highWaterMark - s.queueTotal
+	}
+	return 0
+}
+
+// finishClose moves to closed, s.mutex is held
+func (s *ReadableStream[T]) finishClose() {
+	s.state = stateClosed
+	close(s.done)
+	if s.reader != nil {
+		s.reader.settleClosed(nil)
+	}
+	s.cond.Broadcast()
+}
+
+// errorStream moves to errored, s.mutex is held
+func (s *ReadableStream[T]) errorStream(err error) {
+	if s.state != stateOpen {
+		return
+	}
+	s.state = stateErrored
+	s.storedError = err
+	s.queue = nil
+	s.queueTotal = 0
+	close(s.done)
+	if s.reader != nil {
+		s.reader.settleClosed(err)
+	}
+	s.cond.Broadcast()
+}
+
+// unlock unlocks s.mutex and then settles the Closed promises, whose
+// callbacks run synchronously and may use the stream
+func (s *ReadableStream[T]) unlock() {
+	settling := s.settling
+	s.settling = nil
+	s.mutex.Unlock()
+	for _, settle := range settling {
+		settle()
+	}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultController
+type ReadableStreamDefaultController[T any] struct {
+	stream *ReadableStream[T]
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultController/enqueue
+func (c *ReadableStreamDefaultController[T]) Enqueue(chunk T) error {
+	s := c.stream
+	s.mutex.Lock()
+	defer s.unlock()
+	if s.closeRequested || s.state != stateOpen {
+		return ErrClosed
+	}
+	size := 1.0
+	if s.size != nil {
+		size = s.size(chunk)
+		if size < 0 || math.IsNaN(size) || math.IsInf(size, 0) {
+			err := errors.New("jsstreams: chunk size must be a finite non-negative number")
+			s.errorStream(err)
+			return err
+		}
+	}
+	s.queue = append(s.queue, queueEntry[T]{chunk, size})
+	s.queueTotal += size
+	s.cond.Broadcast()
+	s.pullIfNeeded()
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultController/close
+//
+// The chunks in the queue can still be read
+func (c *ReadableStreamDefaultController[T]) Close() error {
+	s := c.stream
+	s.mutex.Lock()
+	defer s.unlock()
+	if s.closeRequested || s.state != stateOpen {
+		return ErrClosed
+	}
+	s.closeRequested = true
+	if len(s.queue) == 0 {
+		s.finishClose()
+	}
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultController/error
+func (c *ReadableStreamDefaultController[T]) Error(err error) {
+	s := c.stream
+	s.mutex.Lock()
+	defer s.unlock()
+	s.errorStream(err)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultController/desiredSize
+//
+// 0 once the stream is closed or errored
+func (c *ReadableStreamDefaultController[T]) DesiredSize() float64 {
+	s := c.stream
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.desiredSize()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultReader
+type ReadableStreamDefaultReader[T any] struct {
+	stream   *ReadableStream[T]
+	released bool
+	closed   *jspromise.Promise[struct{}]
+	// settles closed while it is pending
+	settle func(err error)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultReader/read
+//
+// Read blocks until there is a chunk, done is true once the stream is closed
+// and the queue is empty.
+func (r *ReadableStreamDefaultReader[T]) Read() (chunk T, done bool, err error) {
+	s := r.stream
+	s.mutex.Lock()
+	defer s.unlock()
+	s.disturbed = true
+	for {
+		if r.released {
+			return chunk, false, ErrReleased
+		}
+		if len(s.queue) > 0 {
+			entry := s.queue[0]
+			var zero queueEntry[T]
+			s.queue[0] = zero
+			s.queue = s.queue[1:]
+			s.queueTotal -= entry.size
+			if len(s.queue) == 0 {
+				s.queueTotal = 0
+				if s.closeRequested {
+					s.finishClose()
+				}
+			}
+			s.pullIfNeeded()
+			return entry.chunk, false, nil
+		}
+		switch s.state {
+		case stateClosed:
+			return chunk, true, nil
+		case stateErrored:
+			return chunk, false, s.storedError
+		}
+		s.waiting++
+		s.pullIfNeeded()
+		s.cond.Wait()
+		s.waiting--
+	}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultReader/releaseLock
+//
+// A Read waiting on another goroutine returns ErrReleased, and so does
+// Closed, like the TypeError in JS.
+func (r *ReadableStreamDefaultReader[T]) ReleaseLock() {
+	s := r.stream
+	s.mutex.Lock()
+	defer s.unlock()
+	if r.released {
+		return
+	}
+	r.released = true
+	s.reader = nil
+	if r.settle != nil {
+		r.settleClosed(ErrReleased)
+	} else if r.closed != nil {
+		r.closed = jspromise.Reject[struct{}](ErrReleased)
+	}
+	s.cond.Broadcast()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultReader/cancel
+func (r *ReadableStreamDefaultReader[T]) Cancel(reason error) error {
+	r.stream.mutex.Lock()
+	released := r.released
+	r.stream.mutex.Unlock()
+	if released {
+		return ErrReleased
+	}
+	return r.stream.cancel(reason)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStreamDefaultReader/closed
+//
+// The Promise fulfills when the stream closes and rejects with its error, or
+// with ErrReleased once the lock is released
+func (r *ReadableStreamDefaultReader[T]) Closed() *jspromise.Promise[struct{}] {
+	s := r.stream
+	s.mutex.Lock()
+	defer s.unlock()
+	if r.closed != nil {
+		return r.closed
+	}
+	promise, resolve, reject := jspromise.WithResolvers[struct{}]()
+	r.closed = promise
+	r.settle = func(err error) {
+		if err != nil {
+			reject(err)
+			return
+		}
+		resolve(struct{}{})
+	}
+	switch {
+	case r.released:
+		r.settleClosed(ErrReleased)
+	case s.state == stateClosed:
+		r.settleClosed(nil)
+	case s.state == stateErrored:
+		r.settleClosed(s.storedError)
+	}
+	return r.closed
+}
+
+// settleClosed settles closed with err once s.mutex is unlocked, s.mutex is
+// held
+func (r *ReadableStreamDefaultReader[T]) settleClosed(err error) {
+	if r.settle == nil {
+		return
+	}
+	settle := r.settle
+	r.settle = nil
+	r.stream.settling = append(r.stream.settling, func() { settle(err) })
+}
@@ -0,0 +1,249 @@
+package jsstreams
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func counter(n int, pulls *atomic.Int32) *ReadableStream[int] {
+	next := 0
+	return NewReadableStream(UnderlyingSource[int]{
+		Pull: func(controller *ReadableStreamDefaultController[int]) error {
+			if pulls != nil {
+				pulls.Add(1)
+			}
+			if next == n {
+				return controller.Close()
+			}
+			next++
+			return controller.Enqueue(next)
+		},
+	})
+}
+
+func readAll[T any](t *testing.T, s *ReadableStream[T]) []T {
+	reader, err := s.GetReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.ReleaseLock()
+	var chunks []T
+	for {
+		chunk, done, err := reader.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			return chunks
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestReadableStream(t *testing.T) {
+	var pulls atomic.Int32
+	s := counter(3, &pulls)
+	time.Sleep(time.Millisecond * 10)
+	if pulls.Load() != 1 {
+		t.Fatal("expect to pull up to the high water mark, got", pulls.Load())
+	}
+	if chunks := readAll(t, s); len(chunks) != 3 || chunks[0] != 1 || chunks[2] != 3 {
+		t.Fatal(chunks)
+	}
+	if !s.Disturbed() || s.Locked() {
+		t.Fatal("expect a disturbed and unlocked stream")
+	}
+
+	s = NewReadableStream(UnderlyingSource[int]{
+		Start: func(controller *ReadableStreamDefaultController[int]) error {
+			controller.Enqueue(1)
+			controller.Enqueue(2)
+			if size := controller.DesiredSize(); size != -1 {
+				t.Fatal(size)
+			}
+			return controller.Close()
+		},
+	})
+	reader, _ := s.GetReader()
+	if _, err := s.GetReader(); err != ErrLocked {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(nil); err != ErrLocked {
+		t.Fatal(err)
+	}
+	if chunk, done, err := reader.Read(); chunk != 1 || done || err != nil {
+		t.Fatal(chunk, done, err)
+	}
+	select {
+	case <-s.done:
+		t.Fatal("expect the stream to close once the queue is empty")
+	default:
+	}
+	reader.Read()
+	if _, done, _ := reader.Read(); !done {
+		t.Fatal("expect done")
+	}
+	if _, err := reader.Closed().Await(); err != nil {
+		t.Fatal(err)
+	}
+	reader.ReleaseLock()
+	if _, _, err := reader.Read(); err != ErrReleased {
+		t.Fatal(err)
+	}
+
+	sentinel := errors.New("sentinel")
+	s = NewReadableStream(UnderlyingSource[int]{
+		Pull: func(controller *ReadableStreamDefaultController[int]) error {
+			return sentinel
+		},
+	})
+	reader, _ = s.GetReader()
+	closed := reader.Closed()
+	if reader.Closed() != closed {
+		t.Fatal("expect one Closed promise per reader")
+	}
+	if _, _, err := reader.Read(); err != sentinel {
+		t.Fatal(err)
+	}
+	if _, err := closed.Await(); err != sentinel {
+		t.Fatal(err)
+	}
+}
+
+func TestReaderClosedReleased(t *testing.T) {
+	s := NewReadableStream(UnderlyingSource[int]{})
+	reader, _ := s.GetReader()
+	closed := reader.Closed()
+	reader.ReleaseLock()
+	if _, err := closed.Await(); err != ErrReleased {
+		t.Fatal(err)
+	}
+	if _, err := reader.Closed().Await(); err != ErrReleased {
+		t.Fatal(err)
+	}
+
+	// the next reader follows the stream again
+	reader, _ = s.GetReader()
+	closed = reader.Closed()
+	s.controller.Close()
+	if _, err := closed.Await(); err != nil {
+		t.Fatal(err)
+	}
+	reader.ReleaseLock()
+	if _, err := reader.Closed().Await(); err != ErrReleased {
+		t.Fatal(err)
+	}
+}
+
+func TestReadableStreamCancel(t *testing.T) {
+	var reason error
+	s := NewReadableStream(UnderlyingSource[int]{
+		Start: func(controller *ReadableStreamDefaultController[int]) error {
+			return controller.Enqueue(1)
+		},
+		Cancel: func(err error) error {
+			reason = err
+			return nil
+		},
+	})
+	reader, _ := s.GetReader()
+	done := make(chan error)
+	go func() {
+		reader.Read()
+		_, _, err := reader.Read()
+		done <- err
+	}()
+	sentinel := errors.New("sentinel")
+	time.Sleep(time.Millisecond * 10)
+	if err := reader.Cancel(sentinel); err != nil || reason != sentinel {
+		t.Fatal(err, reason)
+	}
+	if err := <-done; err != nil {
+		t.Fatal("expect a waiting Read to be done after cancel", err)
+	}
+}
+
+func TestTee(t *testing.T) {
+	s := counter(100, nil)
+	left, right, err := s.Tee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Tee(); err != ErrLocked {
+		t.Fatal(err)
+	}
+	results := make(chan []int)
+	go func() { results <- readAll(t, right) }()
+	for _, chunks := range [][]int{readAll(t, left), <-results} {
+		if len(chunks) != 100 || chunks[99] != 100 {
+			t.Fatal(chunks)
+		}
+	}
+
+	var canceled atomic.Int32
+	s = NewReadableStream(UnderlyingSource[int]{
+		Pull: func(controller *ReadableStreamDefaultController[int]) error {
+			return controller.Enqueue(1)
+		},
+		Cancel: func(reason error) error {
+			canceled.Add(1)
+			return nil
+		},
+	})
+	left, right, _ = s.Tee()
+	left.Cancel(nil)
+	if canceled.Load() != 0 {
+		t.Fatal("expect the source to run until both branches cancel")
+	}
+	right.Cancel(nil)
+	if canceled.Load() != 1 {
+		t.Fatal("expect the source to be canceled")
+	}
+}
+
+func TestPipeTo(t *testing.T) {
+	var written []int
+	var inFlight, peak atomic.Int32
+	dest := NewWritableStream(UnderlyingSink[int]{
+		Write: func(chunk int, controller *WritableStreamDefaultController) error {
+			if n := inFlight.Add(1); n > peak.Load() {
+				peak.Store(n)
+			}
+			defer inFlight.Add(-1)
+			written = append(written, chunk)
+			return nil
+		},
+	})
+	var pulls atomic.Int32
+	if err := counter(10, &pulls).PipeTo(dest); err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 10 || written[9] != 10 || peak.Load() != 1 {
+		t.Fatal(written, peak.Load())
+	}
+	if err := dest.Close(); err != ErrClosed {
+		t.Fatal(err)
+	}
+
+	sentinel := errors.New("sentinel")
+	var canceled atomic.Bool
+	source := NewReadableStream(UnderlyingSource[int]{
+		Pull: func(controller *ReadableStreamDefaultController[int]) error {
+			return controller.Enqueue(1)
+		},
+		Cancel: func(reason error) error {
+			canceled.Store(reason == sentinel)
+			return nil
+		},
+	})
+	dest = NewWritableStream(UnderlyingSink[int]{
+		Write: func(chunk int, controller *WritableStreamDefaultController) error {
+			return sentinel
+		},
+	})
+	if err := source.PipeTo(dest); err != sentinel || !canceled.Load() {
+		t.Fatal(err)
+	}
+}
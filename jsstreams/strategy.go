@@ -0,0 +1,25 @@
+package jsstreams
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ReadableStream/ReadableStream#queuingstrategy
+type QueuingStrategy[T any] struct {
+	// how much the queue holds before the stream stops pulling, or a writer
+	// sees a desired size at or below 0. It is 1 when no strategy is passed.
+	HighWaterMark float64
+	// the size of a chunk, 1 by default
+	Size func(chunk T) float64
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/CountQueuingStrategy
+func CountQueuingStrategy[T any](highWaterMark float64) QueuingStrategy[T] {
+	return QueuingStrategy[T]{HighWaterMark: highWaterMark}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/ByteLengthQueuingStrategy
+func ByteLengthQueuingStrategy(highWaterMark float64) QueuingStrategy[[]byte] {
+	return QueuingStrategy[[]byte]{
+		HighWaterMark: highWaterMark,
+		Size: func(chunk []byte) float64 {
+			return float64(len(chunk))
+		},
+	}
+}
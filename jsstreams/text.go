@@ -0,0 +1,60 @@
+package jsstreams
+
+import (
+	"d1y.io/jslike/jsencoding"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoderStream/TextDecoderStream#options
+type TextDecoderOptions = jsencoding.TextDecoderOptions
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextEncoderStream
+//
+// Strings are UTF-8 in Go, the stream joins a rune split between two chunks
+// and writes U+FFFD for invalid bytes.
+func NewTextEncoderStream() *TransformStream[string, []byte] {
+	// decoding UTF-8 to UTF-8 is what repairs the text
+	decoder, _ := jsencoding.NewTextDecoder("utf-8", jsencoding.TextDecoderOptions{IgnoreBOM: true})
+	encode := func(chunk string, flush bool, controller *TransformStreamDefaultController[[]byte]) error {
+		text, _ := decoder.Decode([]byte(chunk), jsencoding.DecodeOptions{Stream: !flush})
+		if text == "" {
+			return nil
+		}
+		return controller.Enqueue([]byte(text))
+	}
+	return NewTransformStream(Transformer[string, []byte]{
+		Transform: func(chunk string, controller *TransformStreamDefaultController[[]byte]) error {
+			return encode(chunk, false, controller)
+		},
+		Flush: func(controller *TransformStreamDefaultController[[]byte]) error {
+			return encode("", true, controller)
+		},
+	})
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TextDecoderStream/TextDecoderStream
+//
+// label is "" for UTF-8 or any label NewTextDecoder of jsencoding takes
+func NewTextDecoderStream(label string, options ...TextDecoderOptions) (*TransformStream[[]byte, string], error) {
+	decoder, err := jsencoding.NewTextDecoder(label, options...)
+	if err != nil {
+		return nil, err
+	}
+	decode := func(chunk []byte, flush bool, controller *TransformStreamDefaultController[string]) error {
+		text, err := decoder.Decode(chunk, jsencoding.DecodeOptions{Stream: !flush})
+		if err != nil {
+			return err
+		}
+		if text == "" {
+			return nil
+		}
+		return controller.Enqueue(text)
+	}
+	return NewTransformStream(Transformer[[]byte, string]{
+		Transform: func(chunk []byte, controller *TransformStreamDefaultController[string]) error {
+			return decode(chunk, false, controller)
+		},
+		Flush: func(controller *TransformStreamDefaultController[string]) error {
+			return decode(nil, true, controller)
+		},
+	}), nil
+}
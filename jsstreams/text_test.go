@@ -0,0 +1,124 @@
+package jsstreams
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"d1y.io/jslike/jsencoding"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+func chunks[T any](values ...T) *ReadableStream[T] {
+	return NewReadableStream(UnderlyingSource[T]{
+		Start: func(controller *ReadableStreamDefaultController[T]) error {
+			for _, value := range values {
+				controller.Enqueue(value)
+			}
+			return controller.Close()
+		},
+	})
+}
+
+func decodeChunks(t *testing.T, options TextDecoderOptions, values ...[]byte) (string, error) {
+	decoder, err := NewTextDecoderStream("UTF-8", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readable, _ := PipeThrough(chunks(values...), decoder)
+	reader, _ := readable.GetReader()
+	var b strings.Builder
+	for {
+		chunk, done, err := reader.Read()
+		if err != nil {
+			return b.String(), err
+		}
+		if done {
+			return b.String(), nil
+		}
+		b.WriteString(chunk)
+	}
+}
+
+func TestTextDecoderStream(t *testing.T) {
+	euro := []byte("€")
+	cases := []struct {
+		chunks  [][]byte
+		options TextDecoderOptions
+		text    string
+		err     error
+	}{
+		{[][]byte{euro[:1], euro[1:2], euro[2:]}, TextDecoderOptions{}, "€", nil},
+		{[][]byte{utf8BOM[:2], append(utf8BOM[2:], 'a')}, TextDecoderOptions{}, "a", nil},
+		{[][]byte{append(utf8BOM, 'a')}, TextDecoderOptions{IgnoreBOM: true}, "\ufeffa", nil},
+		{[][]byte{{0xE2, 0x82, 'a', 0xFF}}, TextDecoderOptions{}, "�a�", nil},
+		{[][]byte{euro[:2]}, TextDecoderOptions{}, "�", nil},
+		{[][]byte{[]byte("ok"), {0xFF}}, TextDecoderOptions{Fatal: true}, "ok", jsencoding.ErrInvalidData},
+	}
+	for _, c := range cases {
+		if text, err := decodeChunks(t, c.options, c.chunks...); text != c.text || err != c.err {
+			t.Fatalf("%q: expect %q %v, got %q %v", c.chunks, c.text, c.err, text, err)
+		}
+	}
+	if _, err := NewTextDecoderStream("bogus"); err == nil {
+		t.Fatal("expect an unknown encoding")
+	}
+}
+
+func TestTextEncoderStream(t *testing.T) {
+	euro := "€"
+	readable, _ := PipeThrough(chunks("a"+euro[:1], euro[1:], "\xff"), NewTextEncoderStream())
+	var buf bytes.Buffer
+	for _, chunk := range readAll(t, readable) {
+		buf.Write(chunk)
+	}
+	if buf.String() != "a€�" {
+		t.Fatalf("%q", buf.String())
+	}
+}
+
+func TestCompressionStream(t *testing.T) {
+	data := strings.Repeat("jslike streams ", 10000)
+	for _, format := range []string{"gzip", "deflate", "deflate-raw"} {
+		compress, err := NewCompressionStream(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decompress, _ := NewDecompressionStream(format)
+		compressed, _ := PipeThrough(FromReader(strings.NewReader(data)), compress)
+		var size int
+		compressed, _ = PipeThrough(compressed, NewTransformStream(Transformer[[]byte, []byte]{
+			Transform: func(chunk []byte, controller *TransformStreamDefaultController[[]byte]) error {
+				size += len(chunk)
+				return controller.Enqueue(chunk)
+			},
+		}))
+		decompressed, _ := PipeThrough(compressed, decompress)
+		body, err := io.ReadAll(ToReader(decompressed))
+		if err != nil || string(body) != data {
+			t.Fatal(format, len(body), err)
+		}
+		if size == 0 || size >= len(data)/10 {
+			t.Fatal(format, "expect compressed data, got", size)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(data))
+	w.Close()
+	valid := buf.Bytes()
+	for _, input := range [][]byte{valid[:len(valid)/2], append(valid, "junk"...), []byte("not gzip")} {
+		decompress, _ := NewDecompressionStream("gzip")
+		readable, _ := PipeThrough(chunks(input), decompress)
+		if _, err := io.ReadAll(ToReader(readable)); err == nil {
+			t.Fatalf("expect an error for %q", input[len(input)-4:])
+		}
+	}
+	if _, err := NewCompressionStream("br"); err == nil {
+		t.Fatal("expect an unsupported format")
+	}
+}
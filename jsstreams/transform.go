@@ -0,0 +1,231 @@
+package jsstreams
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrTerminated is the error of the writable side of a TransformStream whose
+// controller called Terminate
+var ErrTerminated = errors.New("jsstreams: transform stream was terminated")
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStream/TransformStream#transformer
+type Transformer[I, O any] struct {
+	// called once by NewTransformStream, an error errors the stream
+	Start func(controller *TransformStreamDefaultController[O]) error
+	// called for every chunk written, nil passes the chunks through which
+	// needs I to be O
+	Transform func(chunk I, controller *TransformStreamDefaultController[O]) error
+	// called once the writable side closes, before the readable side does
+	Flush func(controller *TransformStreamDefaultController[O]) error
+	// called when the readable side is canceled or the writable side aborted
+	Cancel func(reason error) error
+}
+
+// TransformStrategy holds the queuing strategies of the two sides of a
+// TransformStream, the writable side holds one chunk and the readable side
+// none by default.
+type TransformStrategy[I, O any] struct {
+	Writable *QueuingStrategy[I]
+	Readable *QueuingStrategy[O]
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStream
+//
+// A write waits until the readable side wants a chunk, so a pipe through the
+// stream moves no faster than it is read.
+type TransformStream[I, O any] struct {
+	mutex        sync.Mutex
+	cond         sync.Cond
+	backpressure bool
+	// why a waiting write gives up, set once either side errors
+	err error
+
+	transformer Transformer[I, O]
+	readable    *ReadableStream[O]
+	writable    *WritableStream[I]
+	controller  *TransformStreamDefaultController[O]
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStream/TransformStream
+func NewTransformStream[I, O any](transformer Transformer[I, O], strategy ...TransformStrategy[I, O]) *TransformStream[I, O] {
+	t := &TransformStream[I, O]{transformer: transformer, backpressure: true}
+	t.cond.L = &t.mutex
+	writableStrategy := QueuingStrategy[I]{HighWaterMark: 1}
+	readableStrategy := QueuingStrategy[O]{HighWaterMark: 0}
+	if len(strategy) > 0 {
+		if strategy[0].Writable != nil {
+			writableStrategy = *strategy[0].Writable
+		}
+		if strategy[0].Readable != nil {
+			readableStrategy = *strategy[0].Readable
+		}
+	}
+
+	t.readable = NewReadableStream(UnderlyingSource[O]{
+		Pull: func(*ReadableStreamDefaultController[O]) error {
+			t.setBackpressure(false)
+			return nil
+		},
+		Cancel: func(reason error) error {
+			t.fail(reason)
+			t.writable.controller.Error(reason)
+			return t.cancel(reason)
+		},
+	}, readableStrategy)
+	t.writable = NewWritableStream(UnderlyingSink[I]{
+		Start: func(controller *WritableStreamDefaultController) error {
+			// an abort wakes a write waiting for the readable side
+			controller.Signal().OnAbort(t.fail)
+			return nil
+		},
+		Write: t.write,
+		Close: t.close,
+		Abort: func(reason error) error {
+			t.readable.controller.Error(reason)
+			return t.cancel(reason)
+		},
+	}, writableStrategy)
+	t.controller = &TransformStreamDefaultController[O]{
+		readable:           t.readable,
+		updateBackpressure: t.updateBackpressure,
+		waitForDemand:      t.waitForDemand,
+		errorWritable: func(err error) {
+			t.fail(err)
+			t.writable.controller.Error(err)
+		},
+	}
+	if transformer.Start != nil {
+		if err := transformer.Start(t.controller); err != nil {
+			t.controller.Error(err)
+		}
+	}
+	return t
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStream/readable
+func (t *TransformStream[I, O]) Readable() *ReadableStream[O] {
+	return t.readable
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStream/writable
+func (t *TransformStream[I, O]) Writable() *WritableStream[I] {
+	return t.writable
+}
+
+func (t *TransformStream[I, O]) write(chunk I, _ *WritableStreamDefaultController) error {
+	if err := t.waitForDemand(); err != nil {
+		return err
+	}
+	if t.transformer.Transform == nil {
+		out, ok := any(chunk).(O)
+		if !ok {
+			err := fmt.Errorf("jsstreams: cannot pass %T through without a transform", chunk)
+			t.readable.controller.Error(err)
+			return err
+		}
+		return t.controller.Enqueue(out)
+	}
+	if err := t.transformer.Transform(chunk, t.controller); err != nil {
+		t.readable.controller.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (t *TransformStream[I, O]) close() error {
+	if t.transformer.Flush != nil {
+		if err := t.transformer.Flush(t.controller); err != nil {
+			t.readable.controller.Error(err)
+			return err
+		}
+	}
+	// already closed when the controller terminated the stream
+	t.readable.controller.Close()
+	r := t.readable
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.state == stateErrored {
+		return r.storedError
+	}
+	return nil
+}
+
+func (t *TransformStream[I, O]) cancel(reason error) error {
+	if t.transformer.Cancel != nil {
+		return t.transformer.Cancel(reason)
+	}
+	return nil
+}
+
+func (t *TransformStream[I, O]) setBackpressure(backpressure bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.backpressure = backpressure
+	t.cond.Broadcast()
+}
+
+// updateBackpressure holds the writes back when the readable side wants no
+// more chunks, a pull in between can't be missed since it takes t.mutex too
+func (t *TransformStream[I, O]) updateBackpressure() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.backpressure = !t.readable.wantsChunks()
+	t.cond.Broadcast()
+}
+
+// waitForDemand blocks until the readable side wants a chunk
+func (t *TransformStream[I, O]) waitForDemand() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for t.backpressure && t.err == nil {
+		t.cond.Wait()
+	}
+	return t.err
+}
+
+func (t *TransformStream[I, O]) fail(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.err == nil {
+		t.err = err
+	}
+	t.cond.Broadcast()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStreamDefaultController
+type TransformStreamDefaultController[O any] struct {
+	readable           *ReadableStream[O]
+	updateBackpressure func()
+	waitForDemand      func() error
+	errorWritable      func(err error)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStreamDefaultController/enqueue
+func (c *TransformStreamDefaultController[O]) Enqueue(chunk O) error {
+	if err := c.readable.controller.Enqueue(chunk); err != nil {
+		return err
+	}
+	c.updateBackpressure()
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStreamDefaultController/error
+func (c *TransformStreamDefaultController[O]) Error(err error) {
+	c.readable.controller.Error(err)
+	c.errorWritable(err)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStreamDefaultController/terminate
+//
+// The readable side closes after its queue, writes fail with ErrTerminated
+func (c *TransformStreamDefaultController[O]) Terminate() {
+	c.readable.controller.Close()
+	c.errorWritable(ErrTerminated)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/TransformStreamDefaultController/desiredSize
+func (c *TransformStreamDefaultController[O]) DesiredSize() float64 {
+	return c.readable.controller.DesiredSize()
+}
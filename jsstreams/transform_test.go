@@ -0,0 +1,137 @@
+package jsstreams
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"d1y.io/jslike/jspromise"
+)
+
+func TestPipeOptions(t *testing.T) {
+	var closed atomic.Bool
+	sink := func() *WritableStream[int] {
+		closed.Store(false)
+		return NewWritableStream(UnderlyingSink[int]{
+			Close: func() error {
+				closed.Store(true)
+				return nil
+			},
+		})
+	}
+	if err := counter(3, nil).PipeTo(sink(), PipeOptions{PreventClose: true}); err != nil || closed.Load() {
+		t.Fatal(err)
+	}
+
+	sentinel := errors.New("sentinel")
+	failing := NewReadableStream(UnderlyingSource[int]{
+		Pull: func(controller *ReadableStreamDefaultController[int]) error {
+			return sentinel
+		},
+	})
+	dest := sink()
+	if err := failing.PipeTo(dest, PipeOptions{PreventAbort: true}); err != sentinel {
+		t.Fatal(err)
+	}
+	if err := dest.Close(); err != nil || !closed.Load() {
+		t.Fatal("expect dest to stay writable", err)
+	}
+
+	controller := jspromise.NewAbortController()
+	var canceled atomic.Bool
+	endless := NewReadableStream(UnderlyingSource[int]{
+		Start: func(controller *ReadableStreamDefaultController[int]) error {
+			return controller.Enqueue(1)
+		},
+		Cancel: func(reason error) error {
+			canceled.Store(reason == sentinel)
+			return nil
+		},
+	})
+	time.AfterFunc(time.Millisecond*10, func() { controller.Abort(sentinel) })
+	dest = sink()
+	if err := endless.PipeTo(dest, PipeOptions{Signal: controller.Signal()}); err != sentinel {
+		t.Fatal(err)
+	}
+	if !canceled.Load() || dest.Close() != sentinel {
+		t.Fatal("expect the source canceled and dest aborted")
+	}
+}
+
+func TestTransformStream(t *testing.T) {
+	upper := NewTransformStream(Transformer[string, string]{
+		Transform: func(chunk string, controller *TransformStreamDefaultController[string]) error {
+			return controller.Enqueue(strings.ToUpper(chunk))
+		},
+		Flush: func(controller *TransformStreamDefaultController[string]) error {
+			return controller.Enqueue("!")
+		},
+	})
+	words := NewReadableStream(UnderlyingSource[string]{
+		Start: func(controller *ReadableStreamDefaultController[string]) error {
+			for _, word := range []string{"hello", " ", "world"} {
+				controller.Enqueue(word)
+			}
+			return controller.Close()
+		},
+	}, CountQueuingStrategy[string](3))
+	readable, err := PipeThrough(words, upper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PipeThrough(words, upper); err != ErrLocked {
+		t.Fatal(err)
+	}
+	if text := strings.Join(readAll(t, readable), ""); text != "HELLO WORLD!" {
+		t.Fatal(text)
+	}
+
+	identity := NewTransformStream(Transformer[int, int]{})
+	writer, _ := identity.Writable().GetWriter()
+	wrote := make(chan error)
+	go func() { wrote <- writer.Write(1) }()
+	select {
+	case <-wrote:
+		t.Fatal("expect the write to wait for a reader")
+	case <-time.After(time.Millisecond * 10):
+	}
+	reader, _ := identity.Readable().GetReader()
+	if chunk, _, err := reader.Read(); chunk != 1 || err != nil || <-wrote != nil {
+		t.Fatal(chunk, err)
+	}
+
+	first := NewTransformStream(Transformer[int, int]{
+		Transform: func(chunk int, controller *TransformStreamDefaultController[int]) error {
+			controller.Enqueue(chunk)
+			controller.Terminate()
+			return nil
+		},
+	})
+	results := make(chan []int)
+	go func() { results <- readAll(t, first.Readable()) }()
+	if err := counter(3, nil).PipeTo(first.Writable()); err != ErrTerminated {
+		t.Fatal(err)
+	}
+	if chunks := <-results; len(chunks) != 1 {
+		t.Fatal(chunks)
+	}
+
+	var reason error
+	blocked := NewTransformStream(Transformer[int, int]{
+		Cancel: func(err error) error {
+			reason = err
+			return nil
+		},
+	})
+	writer, _ = blocked.Writable().GetWriter()
+	go func() { wrote <- writer.Write(1) }()
+	time.Sleep(time.Millisecond * 10)
+	blocked.Readable().Cancel(sentinelError)
+	if err := <-wrote; err != sentinelError || reason != sentinelError {
+		t.Fatal("expect a waiting write to fail once the readable side is canceled", err)
+	}
+}
+
+var sentinelError = errors.New("sentinel")
@@ -0,0 +1,299 @@
+package jsstreams
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"d1y.io/jslike/jspromise"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStream/WritableStream#underlyingsink
+//
+// Write and Close never run at the same time, a call waits for the one before
+// it to return.
+type UnderlyingSink[T any] struct {
+	// called once by NewWritableStream, an error errors the stream
+	Start func(controller *WritableStreamDefaultController) error
+	Write func(chunk T, controller *WritableStreamDefaultController) error
+	Close func() error
+	// called after the Write in progress returns when a producer aborts, a
+	// long Write can watch the Signal of the controller to return early
+	Abort func(reason error) error
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStream
+type WritableStream[T any] struct {
+	mutex          sync.Mutex
+	state          streamState
+	storedError    error
+	closeRequested bool
+	writer         *WritableStreamDefaultWriter[T]
+	// the size of the writes waiting for the sink or in progress
+	queueTotal float64
+
+	// serializes the calls to the sink
+	sinkMutex     sync.Mutex
+	sink          UnderlyingSink[T]
+	highWaterMark float64
+	size          func(chunk T) float64
+	controller    *WritableStreamDefaultController
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStream/WritableStream
+func NewWritableStream[T any](sink UnderlyingSink[T], strategy ...QueuingStrategy[T]) *WritableStream[T] {
+	s := &WritableStream[T]{sink: sink, highWaterMark: 1}
+	if len(strategy) > 0 {
+		s.highWaterMark = strategy[0].HighWaterMark
+		s.size = strategy[0].Size
+	}
+	s.controller = &WritableStreamDefaultController{
+		abortController: jspromise.NewAbortController(),
+		errorStream: func(err error) {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.errorStream(err)
+		},
+	}
+	if sink.Start != nil {
+		if err := sink.Start(s.controller); err != nil {
+			s.controller.Error(err)
+		}
+	}
+	return s
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStream/locked
+func (s *WritableStream[T]) Locked() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.writer != nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStream/getWriter
+func (s *WritableStream[T]) GetWriter() (*WritableStreamDefaultWriter[T], error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.writer != nil {
+		return nil, ErrLocked
+	}
+	s.writer = &WritableStreamDefaultWriter[T]{stream: s}
+	return s.writer, nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStream/abort
+func (s *WritableStream[T]) Abort(reason error) error {
+	if s.Locked() {
+		return ErrLocked
+	}
+	return s.abort(reason)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStream/close
+func (s *WritableStream[T]) Close() error {
+	if s.Locked() {
+		return ErrLocked
+	}
+	return s.close()
+}
+
+func (s *WritableStream[T]) write(chunk T) error {
+	size := 1.0
+	if s.size != nil {
+		size = s.size(chunk)
+		if size < 0 || math.IsNaN(size) || math.IsInf(size, 0) {
+			return errors.New("jsstreams: chunk size must be a finite non-negative number")
+		}
+	}
+	s.mutex.Lock()
+	if err := s.writable(); err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+	s.queueTotal += size
+	s.mutex.Unlock()
+	defer func() {
+		s.mutex.Lock()
+		s.queueTotal -= size
+		s.mutex.Unlock()
+	}()
+
+	s.sinkMutex.Lock()
+	defer s.sinkMutex.Unlock()
+	s.mutex.Lock()
+	if s.state == stateErrored {
+		// aborted while waiting for the writes before it
+		s.mutex.Unlock()
+		return s.storedError
+	}
+	s.mutex.Unlock()
+	if s.sink.Write == nil {
+		return nil
+	}
+	if err := s.sink.Write(chunk, s.controller); err != nil {
+		s.mutex.Lock()
+		s.errorStream(err)
+		s.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s *WritableStream[T]) close() error {
+	s.mutex.Lock()
+	if err := s.writable(); err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+	s.closeRequested = true
+	s.mutex.Unlock()
+
+	s.sinkMutex.Lock()
+	defer s.sinkMutex.Unlock()
+	s.mutex.Lock()
+	if s.state == stateErrored {
+		s.mutex.Unlock()
+		return s.storedError
+	}
+	s.mutex.Unlock()
+	var err error
+	if s.sink.Close != nil {
+		err = s.sink.Close()
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err != nil {
+		s.errorStream(err)
+		return err
+	}
+	s.state = stateClosed
+	return nil
+}
+
+func (s *WritableStream[T]) abort(reason error) error {
+	s.mutex.Lock()
+	if s.state != stateOpen {
+		s.mutex.Unlock()
+		return nil
+	}
+	if reason == nil {
+		reason = errors.New("jsstreams: stream was aborted")
+	}
+	s.errorStream(reason)
+	s.mutex.Unlock()
+	s.controller.abortController.Abort(reason)
+
+	s.sinkMutex.Lock()
+	defer s.sinkMutex.Unlock()
+	if s.sink.Abort != nil {
+		return s.sink.Abort(reason)
+	}
+	return nil
+}
+
+// writable returns why the stream takes no more writes, s.mutex is held
+func (s *WritableStream[T]) writable() error {
+	switch {
+	case s.state == stateErrored:
+		return s.storedError
+	case s.state == stateClosed || s.closeRequested:
+		return ErrClosed
+	}
+	return nil
+}
+
+// errorStream moves to errored, s.mutex is held
+func (s *WritableStream[T]) errorStream(err error) {
+	if s.state != stateOpen {
+		return
+	}
+	s.state = stateErrored
+	s.storedError = err
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultController
+type WritableStreamDefaultController struct {
+	abortController *jspromise.AbortController
+	errorStream     func(err error)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultController/signal
+//
+// The signal aborts with the reason when the stream is aborted
+func (c *WritableStreamDefaultController) Signal() *jspromise.AbortSignal {
+	return c.abortController.Signal()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultController/error
+func (c *WritableStreamDefaultController) Error(err error) {
+	c.errorStream(err)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultWriter
+type WritableStreamDefaultWriter[T any] struct {
+	stream   *WritableStream[T]
+	released bool
+}
+
+func (w *WritableStreamDefaultWriter[T]) check() error {
+	w.stream.mutex.Lock()
+	defer w.stream.mutex.Unlock()
+	if w.released {
+		return ErrReleased
+	}
+	return nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultWriter/write
+//
+// Write blocks until the sink has taken chunk, writes from several goroutines
+// reach the sink one at a time.
+func (w *WritableStreamDefaultWriter[T]) Write(chunk T) error {
+	if err := w.check(); err != nil {
+		return err
+	}
+	return w.stream.write(chunk)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultWriter/close
+//
+// Close waits for the writes before it
+func (w *WritableStreamDefaultWriter[T]) Close() error {
+	if err := w.check(); err != nil {
+		return err
+	}
+	return w.stream.close()
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultWriter/abort
+func (w *WritableStreamDefaultWriter[T]) Abort(reason error) error {
+	if err := w.check(); err != nil {
+		return err
+	}
+	return w.stream.abort(reason)
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultWriter/desiredSize
+//
+// Below or at 0 the sink is behind, 0 once the stream is closed or errored
+func (w *WritableStreamDefaultWriter[T]) DesiredSize() float64 {
+	s := w.stream
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.state != stateOpen || s.closeRequested {
+		return 0
+	}
+	return s.highWaterMark - s.queueTotal
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/API/WritableStreamDefaultWriter/releaseLock
+func (w *WritableStreamDefaultWriter[T]) ReleaseLock() {
+	s := w.stream
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if w.released {
+		return
+	}
+	w.released = true
+	s.writer = nil
+}
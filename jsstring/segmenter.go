@@ -0,0 +1,219 @@
+package jsstring
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Segmenter/Segmenter#granularity
+type Granularity string
+
+const (
+	GranularityGrapheme Granularity = "grapheme"
+	GranularityWord     Granularity = "word"
+	GranularitySentence Granularity = "sentence"
+)
+
+type SegmenterOptions struct {
+	// defaults to GranularityGrapheme
+	Granularity Granularity
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Segmenter
+//
+// The boundaries follow UAX #29 (https://unicode.org/reports/tr29/) without
+// locale tailoring, so unlike ICU, Chinese and Japanese text is not split into
+// dictionary words, every ideograph is its own word.
+type Segmenter struct {
+	locale      string
+	granularity Granularity
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Segmenter/Segmenter
+func NewSegmenter(locale string, options ...SegmenterOptions) *Segmenter {
+	granularity := GranularityGrapheme
+	if len(options) == 1 && options[0].Granularity != "" {
+		granularity = options[0].Granularity
+	}
+	return &Segmenter{locale: locale, granularity: granularity}
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Segmenter/resolvedOptions
+func (seg *Segmenter) ResolvedOptions() ResolvedSegmenterOptions {
+	return ResolvedSegmenterOptions{Locale: seg.locale, Granularity: seg.granularity}
+}
+
+type ResolvedSegmenterOptions struct {
+	Locale      string
+	Granularity Granularity
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Segmenter/segment
+func (seg *Segmenter) Segment(input JSString) Segments {
+	return Segments{input: input, granularity: seg.granularity}
+}
+
+// SegmentData is a record yielded by Segments, Index counts UTF-16 code units
+// like JS does, IsWordLike is only set for GranularityWord
+type SegmentData struct {
+	Segment    JSString
+	Index      int
+	Input      JSString
+	IsWordLike bool
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Segmenter/segment/Segments
+type Segments struct {
+	input       JSString
+	granularity Granularity
+}
+
+// Each calls f for every segment in order until f returns false
+func (s Segments) Each(f func(SegmentData) bool) {
+	rest := string(s.input)
+	index := 0
+	state := -1
+	for len(rest) > 0 {
+		var segment string
+		switch s.granularity {
+		case GranularityWord:
+			segment, rest, state = uniseg.FirstWordInString(rest, state)
+		case GranularitySentence:
+			segment, rest, state = uniseg.FirstSentenceInString(rest, state)
+		default:
+			segment, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		}
+		data := SegmentData{Segment: JSString(segment), Index: index, Input: s.input}
+		if s.granularity == GranularityWord {
+			data.IsWordLike = isWordLike(segment)
+		}
+		if !f(data) {
+			return
+		}
+		index += utf16Length(segment)
+	}
+}
+
+// All returns every segment of the input
+func (s Segments) All() []SegmentData {
+	var result []SegmentData
+	s.Each(func(data SegmentData) bool {
+		result = append(result, data)
+		return true
+	})
+	return result
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/Intl/Segmenter/segment/Segments/containing
+func (s Segments) Containing(index int) (SegmentData, bool) {
+	var result SegmentData
+	found := false
+	if index < 0 {
+		return result, false
+	}
+	s.Each(func(data SegmentData) bool {
+		if index < data.Index+utf16Length(string(data.Segment)) {
+			result, found = data, true
+			return false
+		}
+		return true
+	})
+	return result, found
+}
+
+// Graphemes splits s into user-perceived characters
+func (s JSString) Graphemes() []JSString {
+	var result []JSString
+	Segments{input: s, granularity: GranularityGrapheme}.Each(func(data SegmentData) bool {
+		result = append(result, data.Segment)
+		return true
+	})
+	return result
+}
+
+// GraphemeLength is Length counted in user-perceived characters
+func (s JSString) GraphemeLength() int {
+	return uniseg.GraphemeClusterCount(string(s))
+}
+
+// SliceGraphemes is Slice with indexes counted in user-perceived characters,
+// so it never cuts an emoji or a combining sequence in half
+func (s JSString) SliceGraphemes(indexStart int, indexEnds ...int) JSString {
+	graphemes := s.Graphemes()
+	length := len(graphemes)
+	indexEnd := length
+	if len(indexEnds) == 1 {
+		indexEnd = indexEnds[0]
+	}
+	if indexStart < 0 {
+		indexStart = max(indexStart+length, 0)
+	}
+	if indexEnd < 0 {
+		indexEnd = max(indexEnd+length, 0)
+	}
+	indexStart = min(indexStart, length)
+	indexEnd = min(indexEnd, length)
+	if indexEnd <= indexStart {
+		return ""
+	}
+	var result JSString
+	for _, grapheme := range graphemes[indexStart:indexEnd] {
+		result += grapheme
+	}
+	return result
+}
+
+// PadStartGraphemes is PadStart with targetLength counted in user-perceived
+// characters, the fill is cut between graphemes of padString. padString is a
+// space by default like in JS.
+func (s JSString) PadStartGraphemes(targetLength int, padString ...string) JSString {
+	return graphemeFill(targetLength-s.GraphemeLength(), padString...) + s
+}
+
+// PadEndGraphemes is PadEnd with targetLength counted in user-perceived
+// characters
+func (s JSString) PadEndGraphemes(targetLength int, padString ...string) JSString {
+	return s + graphemeFill(targetLength-s.GraphemeLength(), padString...)
+}
+
+// graphemeFill repeats the graphemes of padString until there are n of them
+func graphemeFill(n int, padString ...string) JSString {
+	fillString := JSString(" ")
+	if len(padString) == 1 {
+		fillString = JSString(padString[0])
+	}
+	graphemes := fillString.Graphemes()
+	if n <= 0 || len(graphemes) == 0 {
+		return ""
+	}
+	var fill strings.Builder
+	for i := 0; i < n; i++ {
+		fill.WriteString(string(graphemes[i%len(graphemes)]))
+	}
+	return JSString(fill.String())
+}
+
+// isWordLike mirrors ICU's rule status, a word segment is word-like when it
+// contains letters, digits or ideographs rather than spaces and punctuation
+func isWordLike(segment string) bool {
+	for _, r := range segment {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func utf16Length(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
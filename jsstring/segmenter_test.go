@@ -0,0 +1,70 @@
+package jsstring
+
+import "testing"
+
+func TestSegmenter(t *testing.T) {
+	t.Run("test grapheme", func(t *testing.T) {
+		var str JSString = "a👨‍👩‍👧é!"
+		segments := NewSegmenter("en").Segment(str).All()
+		if len(segments) != 4 || segments[1].Segment != "👨‍👩‍👧" {
+			t.Fatal(segments)
+		}
+		// the family emoji is 8 UTF-16 code units
+		if segments[2].Index != 9 || segments[3].Index != 10 {
+			t.Fatal(segments)
+		}
+		data, ok := NewSegmenter("en").Segment(str).Containing(5)
+		if !ok || data.Index != 1 {
+			t.FailNow()
+		}
+		if _, ok := NewSegmenter("en").Segment(str).Containing(12); ok {
+			t.FailNow()
+		}
+	})
+
+	t.Run("test word", func(t *testing.T) {
+		segments := NewSegmenter("en", SegmenterOptions{Granularity: GranularityWord}).Segment("Hello, world").All()
+		if len(segments) != 4 {
+			t.Fatal(segments)
+		}
+		if !segments[0].IsWordLike || segments[1].IsWordLike || segments[2].IsWordLike || !segments[3].IsWordLike {
+			t.Fatal(segments)
+		}
+		if segments[3].Segment != "world" || segments[3].Index != 7 {
+			t.Fatal(segments)
+		}
+	})
+
+	t.Run("test sentence", func(t *testing.T) {
+		segments := NewSegmenter("en", SegmenterOptions{Granularity: GranularitySentence}).Segment("Hi there. How are you?").All()
+		if len(segments) != 2 || segments[1].Segment != "How are you?" {
+			t.Fatal(segments)
+		}
+	})
+
+	t.Run("test graphemes slice", func(t *testing.T) {
+		var str JSString = "🇨🇳🇺🇸ab"
+		if str.GraphemeLength() != 4 {
+			t.FailNow()
+		}
+		if str.SliceGraphemes(1, -1).NotEqual("🇺🇸a") {
+			t.FailNow()
+		}
+	})
+
+	t.Run("test graphemes pad", func(t *testing.T) {
+		var str JSString = "é👍🏽"
+		if got := str.PadStartGraphemes(5, "🇨🇳-"); got.NotEqual("🇨🇳-🇨🇳é👍🏽") {
+			t.Fatal(got)
+		}
+		if got := str.PadEndGraphemes(4); got.NotEqual("é👍🏽  ") {
+			t.Fatal(got)
+		}
+		if got := str.PadEndGraphemes(1, "-"); got != str {
+			t.Fatal(got)
+		}
+		if got := str.PadStartGraphemes(4, ""); got != str {
+			t.Fatal(got)
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"math"
 	"strings"
 	"unicode"
+
+	"d1y.io/jslike/jsintl"
 )
 
 type JSString string
@@ -254,17 +256,39 @@ func (s JSString) Substring(indexStart int, indexEnds ...int) JSString {
 	return s[indexStart:]
 }
 
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/String/localeCompare
+//
+// It is jsintl.LocaleCompare, invalid options return an error like the
+// RangeError in JS. Sorting many strings is faster with a jsintl.Collator.
+func (s JSString) LocaleCompare(compareString string, locale string, options ...jsintl.CollatorOptions) (int, error) {
+	return jsintl.LocaleCompare(string(s), compareString, locale, options...)
+}
+
 // https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/String/trim
 func (s JSString) Trim() JSString {
-	return JSString(strings.TrimSpace(string(s)))
+	return JSString(strings.TrimFunc(string(s), isWhiteSpace))
 }
 
 // https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/String/trimStart
 func (s JSString) TrimStart() JSString {
-	return JSString(strings.TrimLeft(string(s), " "))
+	return JSString(strings.TrimLeftFunc(string(s), isWhiteSpace))
 }
 
 // https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/String/trimEnd
 func (s JSString) TrimEnd() JSString {
-	return JSString(strings.TrimRight(string(s), " "))
+	return JSString(strings.TrimRightFunc(string(s), isWhiteSpace))
+}
+
+// isWhiteSpace reports whether r is in the ECMAScript WhiteSpace or
+// LineTerminator set
+//
+// https://tc39.es/ecma262/#sec-white-space
+//
+// https://tc39.es/ecma262/#sec-line-terminators
+func isWhiteSpace(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r', ' ', '\u00a0', '\ufeff', '\u2028', '\u2029':
+		return true
+	}
+	return unicode.Is(unicode.Zs, r)
 }
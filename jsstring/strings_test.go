@@ -2,6 +2,8 @@ package jsstring
 
 import (
 	"testing"
+
+	"d1y.io/jslike/jsintl"
 )
 
 func TestStrings(t *testing.T) {
@@ -52,3 +54,36 @@ func TestStrings(t *testing.T) {
 		}
 	})
 }
+
+func TestTrimWhiteSpace(t *testing.T) {
+	var str JSString = "\u00a0\ufeff\t hello \u3000\n"
+	if str.Trim().NotEqual("hello") {
+		t.FailNow()
+	}
+	if str.TrimStart().NotEqual("hello \u3000\n") {
+		t.FailNow()
+	}
+	if str.TrimEnd().NotEqual("\u00a0\ufeff\t hello") {
+		t.FailNow()
+	}
+	// NEL is white space for Go but not for JS
+	if JSString("\u0085a").Trim().NotEqual("\u0085a") {
+		t.FailNow()
+	}
+}
+
+func TestLocaleCompare(t *testing.T) {
+	var str JSString = "réservé"
+	if n, err := str.LocaleCompare("RESERVE", "en"); err != nil || n != 1 {
+		t.Fatal(n, err)
+	}
+	if n, err := str.LocaleCompare("RESERVE", "en", jsintl.CollatorOptions{Sensitivity: "base"}); err != nil || n != 0 {
+		t.Fatal(n, err)
+	}
+	if n, err := JSString("a").LocaleCompare("b", ""); err != nil || n != -1 {
+		t.Fatal(n, err)
+	}
+	if _, err := str.LocaleCompare("b", "en", jsintl.CollatorOptions{Usage: "bad"}); err == nil {
+		t.Fatal("expect an error for an invalid usage")
+	}
+}
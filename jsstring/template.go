@@ -0,0 +1,197 @@
+package jsstring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Template is what a JS tag function receives for a tagged template literal:
+// the cooked strings, the raw strings and the substitutions between them.
+//
+// Go has no template literals, so a Template is built from the raw source text
+// of each literal chunk (a Go raw string works well for that) and the cooked
+// strings are computed with the JS escape rules.
+//
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Template_literals#%E5%B8%A6%E6%A0%87%E7%AD%BE%E7%9A%84%E6%A8%A1%E6%9D%BF
+type Template struct {
+	// cooked strings, an entry is "" when its raw chunk has an invalid escape
+	// (JS uses undefined there), check it with IsCooked
+	Strings []string
+	// raw strings, with CR and CRLF normalized to LF like JS does
+	Raw           []string
+	Substitutions []any
+	invalid       []bool
+}
+
+// NewTemplate creates a Template from the raw chunks of a template literal,
+// raw must contain exactly one more item than substitutions
+//
+//	// js: tag`Hello\n${name}!`
+//	t, err := NewTemplate([]string{`Hello\n`, `!`}, name)
+func NewTemplate(raw []string, substitutions ...any) (Template, error) {
+	if len(raw) != len(substitutions)+1 {
+		return Template{}, fmt.Errorf("jsstring: a template needs exactly one more string than substitutions, got %d and %d", len(raw), len(substitutions))
+	}
+	t := Template{
+		Strings:       make([]string, len(raw)),
+		Raw:           make([]string, len(raw)),
+		Substitutions: substitutions,
+		invalid:       make([]bool, len(raw)),
+	}
+	for idx, chunk := range raw {
+		chunk = strings.ReplaceAll(chunk, "\r\n", "\n")
+		chunk = strings.ReplaceAll(chunk, "\r", "\n")
+		t.Raw[idx] = chunk
+		cooked, ok := cook(chunk)
+		t.Strings[idx] = cooked
+		t.invalid[idx] = !ok
+	}
+	return t, nil
+}
+
+// IsCooked reports whether the i-th chunk has a cooked value, it is false for
+// chunks with invalid escapes such as `\unicode`
+func (t Template) IsCooked(i int) bool {
+	return i >= 0 && i < len(t.invalid) && !t.invalid[i]
+}
+
+// Interpolate joins the cooked strings and the substitutions like an
+// untagged template literal does, a chunk with an invalid escape is an error
+// like the SyntaxError in JS
+func (t Template) Interpolate() (string, error) {
+	var sb strings.Builder
+	for idx, str := range t.Strings {
+		if !t.IsCooked(idx) {
+			return "", fmt.Errorf("jsstring: invalid escape sequence in template string %q", t.Raw[idx])
+		}
+		sb.WriteString(str)
+		if idx < len(t.Substitutions) {
+			sb.WriteString(fmt.Sprint(t.Substitutions[idx]))
+		}
+	}
+	return sb.String(), nil
+}
+
+// https://developer.mozilla.org/zh-CN/docs/Web/JavaScript/Reference/Global_Objects/String/raw
+func Raw(t Template) JSString {
+	var sb strings.Builder
+	for idx, str := range t.Raw {
+		sb.WriteString(str)
+		if idx < len(t.Raw)-1 && idx < len(t.Substitutions) {
+			sb.WriteString(fmt.Sprint(t.Substitutions[idx]))
+		}
+	}
+	return JSString(sb.String())
+}
+
+// cook applies the JS template escape sequences to a raw chunk
+//
+// https://tc39.es/ecma262/#sec-static-semantics-tv
+func cook(raw string) (string, bool) {
+	if !strings.Contains(raw, `\`) {
+		return raw, true
+	}
+	var sb strings.Builder
+	var high rune // pending high surrogate from a \u escape
+	flush := func() {
+		if high != 0 {
+			sb.WriteRune(utf8.RuneError)
+			high = 0
+		}
+	}
+	writeUnit := func(r rune) {
+		switch {
+		case r >= 0xD800 && r <= 0xDBFF:
+			flush()
+			high = r
+		case r >= 0xDC00 && r <= 0xDFFF:
+			if high != 0 {
+				sb.WriteRune((high-0xD800)<<10 + (r - 0xDC00) + 0x10000)
+				high = 0
+			} else {
+				sb.WriteRune(utf8.RuneError)
+			}
+		default:
+			flush()
+			sb.WriteRune(r)
+		}
+	}
+	for i := 0; i < len(raw); {
+		if raw[i] != '\\' {
+			flush()
+			r, size := utf8.DecodeRuneInString(raw[i:])
+			sb.WriteRune(r)
+			i += size
+			continue
+		}
+		i++
+		if i >= len(raw) {
+			return "", false
+		}
+		r, size := utf8.DecodeRuneInString(raw[i:])
+		i += size
+		switch r {
+		case 'b':
+			writeUnit('\b')
+		case 't':
+			writeUnit('\t')
+		case 'n':
+			writeUnit('\n')
+		case 'v':
+			writeUnit('\v')
+		case 'f':
+			writeUnit('\f')
+		case 'r':
+			writeUnit('\r')
+		case '\n', '\u2028', '\u2029':
+			// line continuation
+			flush()
+		case '0':
+			if i < len(raw) && raw[i] >= '0' && raw[i] <= '9' {
+				return "", false
+			}
+			writeUnit(0)
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return "", false
+		case 'x':
+			if i+2 > len(raw) {
+				return "", false
+			}
+			v, err := strconv.ParseUint(raw[i:i+2], 16, 8)
+			if err != nil {
+				return "", false
+			}
+			writeUnit(rune(v))
+			i += 2
+		case 'u':
+			if i < len(raw) && raw[i] == '{' {
+				end := strings.IndexByte(raw[i:], '}')
+				if end < 2 {
+					return "", false
+				}
+				v, err := strconv.ParseUint(raw[i+1:i+end], 16, 32)
+				if err != nil || v > utf8.MaxRune {
+					return "", false
+				}
+				writeUnit(rune(v))
+				i += end + 1
+				continue
+			}
+			if i+4 > len(raw) {
+				return "", false
+			}
+			v, err := strconv.ParseUint(raw[i:i+4], 16, 16)
+			if err != nil {
+				return "", false
+			}
+			writeUnit(rune(v))
+			i += 4
+		default:
+			writeUnit(r)
+		}
+	}
+	flush()
+	return sb.String(), true
+}
@@ -0,0 +1,54 @@
+package jsstring
+
+import "testing"
+
+func TestTemplate(t *testing.T) {
+	t.Run("test cooked and raw", func(t *testing.T) {
+		tpl, err := NewTemplate([]string{`Hi\n`, `\u{1F600}\x41`, `A😀`}, 1, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tpl.Strings[0] != "Hi\n" || tpl.Strings[1] != "😀A" || tpl.Strings[2] != "A😀" {
+			t.Fatal(tpl.Strings)
+		}
+		if str, err := tpl.Interpolate(); err != nil || str != "Hi\n1😀AbA😀" {
+			t.Fatal(str, err)
+		}
+		if Raw(tpl).NotEqual(`Hi\n1\u{1F600}\x41bA😀`) {
+			t.Fatal(Raw(tpl))
+		}
+	})
+
+	t.Run("test invalid escape", func(t *testing.T) {
+		tpl, _ := NewTemplate([]string{`\unicode and \u{55}`})
+		if tpl.IsCooked(0) || tpl.Raw[0] != `\unicode and \u{55}` {
+			t.FailNow()
+		}
+		if _, err := tpl.Interpolate(); err == nil {
+			t.Fatal("expect an error for an invalid escape")
+		}
+		if Raw(tpl).NotEqual(`\unicode and \u{55}`) {
+			t.Fatal(Raw(tpl))
+		}
+		tpl, _ = NewTemplate([]string{"a\\\nb"})
+		if !tpl.IsCooked(0) || tpl.Strings[0] != "ab" {
+			t.FailNow()
+		}
+	})
+
+	t.Run("test line endings", func(t *testing.T) {
+		tpl, _ := NewTemplate([]string{"a\r\nb\rc"})
+		if tpl.Raw[0] != "a\nb\nc" {
+			t.FailNow()
+		}
+	})
+
+	t.Run("test substitution count", func(t *testing.T) {
+		if _, err := NewTemplate([]string{"a", "b"}); err == nil {
+			t.Fatal("expect an error for a missing substitution")
+		}
+		if _, err := NewTemplate(nil); err == nil {
+			t.Fatal("expect an error for no strings")
+		}
+	})
+}